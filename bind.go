@@ -1,22 +1,307 @@
 package twiml
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
 	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/gorilla/schema"
 )
 
-var decoder = schema.NewDecoder()
+// maxMemory is the amount of a multipart body that will be held in memory while binding
+const maxMemory = 32 << 20
+
+// UnsupportedContentTypeError is returned by Bind when the request body is in a format
+// that can not be bound to a callback request
+type UnsupportedContentTypeError struct {
+	ContentType string
+}
+
+// Error returns a string representation of the unsupported content type
+func (u UnsupportedContentTypeError) Error() string {
+	return fmt.Sprintf("Unsupported content type for callback request: '%s'", u.ContentType)
+}
+
+// Binder marshals callback requests from the Twilio API into structs.  A Binder is
+// configured once when it is created and is safe for concurrent use.
+type Binder struct {
+	decoder *schema.Decoder
+	strict  bool
+	tag     string
+}
+
+// BinderOption configures a Binder created with NewBinder
+type BinderOption func(b *Binder)
+
+// Strict returns a BindError when a callback request contains parameters that are not
+// fields of the struct being bound, or is missing a parameter for a field tagged
+// `twiml:"required"`.  By default unknown parameters are ignored and required fields
+// are not checked.
+func Strict() BinderOption {
+	return func(b *Binder) {
+		b.strict = true
+	}
+}
+
+// Converter registers a function to convert parameters into fields of the same type as value
+func Converter(value interface{}, fn schema.Converter) BinderOption {
+	return func(b *Binder) {
+		b.decoder.RegisterConverter(value, fn)
+	}
+}
+
+// ZeroEmpty sets fields to their zero value when the parameter is present but empty.
+// By default empty parameters leave the field unchanged.
+func ZeroEmpty(z bool) BinderOption {
+	return func(b *Binder) {
+		b.decoder.ZeroEmpty(z)
+	}
+}
+
+// AliasTag changes the struct tag used to map parameters to fields.  The default tag is "schema".
+func AliasTag(tag string) BinderOption {
+	return func(b *Binder) {
+		b.decoder.SetAliasTag(tag)
+		b.tag = tag
+	}
+}
+
+// NewBinder creates a new Binder configured with the options provided
+func NewBinder(opts ...BinderOption) *Binder {
+	b := &Binder{decoder: schema.NewDecoder(), tag: "schema"}
+	for _, opt := range opts {
+		opt(b)
+	}
+	b.decoder.IgnoreUnknownKeys(!b.strict)
+	return b
+}
 
 // Bind will marshal a callback request from the Twilio API
-// into the cbRequest struct provided
-func Bind(cbRequest interface{}, r *http.Request) error {
-	if err := r.ParseForm(); err != nil {
+// into the cbRequest struct provided.  Parameters are read from the query string
+// for GET requests, otherwise from a form encoded, multipart or JSON request body.
+func (b *Binder) Bind(cbRequest interface{}, r *http.Request) error {
+	values, err := requestValues(r)
+	if err != nil {
 		return err
 	}
-	decoder.IgnoreUnknownKeys(true)
-	if err := decoder.Decode(cbRequest, r.PostForm); err != nil {
-		return err
+	var bindErr BindError
+	if err := b.decoder.Decode(cbRequest, values); err != nil {
+		multi, ok := err.(schema.MultiError)
+		if !ok {
+			return err
+		}
+		bindErr = newBindError(multi)
+	}
+	if b.strict {
+		bindErr.Missing = append(bindErr.Missing, b.missing(reflect.TypeOf(cbRequest), values)...)
+		sort.Strings(bindErr.Missing)
+	}
+	if !bindErr.empty() {
+		return bindErr
+	}
+	return nil
+}
+
+// missing returns the parameters for fields tagged `twiml:"required"` that have no value
+func (b *Binder) missing(t reflect.Type, values url.Values) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			keys = append(keys, b.missing(f.Type, values)...)
+			continue
+		}
+		if f.Tag.Get("twiml") != "required" {
+			continue
+		}
+		key := strings.Split(f.Tag.Get(b.tag), ",")[0]
+		if key == "" {
+			key = f.Name
+		}
+		if len(values[key]) == 0 || values[key][0] == "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// BindError is returned when parameters of a callback request can not be bound to the struct
+// provided.  Unknown parameters and missing required parameters are only reported by a Strict binder.
+type BindError struct {
+	// Unknown lists parameters that do not match any field
+	Unknown []string
+	// Malformed holds the conversion error for each parameter that could not be converted to its field type
+	Malformed map[string]error
+	// Missing lists required parameters that were not present
+	Missing []string
+}
+
+func newBindError(multi schema.MultiError) BindError {
+	var b BindError
+	for key, err := range multi {
+		switch err.(type) {
+		case schema.UnknownKeyError:
+			b.Unknown = append(b.Unknown, key)
+		case schema.EmptyFieldError:
+			b.Missing = append(b.Missing, key)
+		default:
+			if b.Malformed == nil {
+				b.Malformed = make(map[string]error)
+			}
+			b.Malformed[key] = err
+		}
+	}
+	sort.Strings(b.Unknown)
+	sort.Strings(b.Missing)
+	return b
+}
+
+func (b BindError) empty() bool {
+	return len(b.Unknown) == 0 && len(b.Malformed) == 0 && len(b.Missing) == 0
+}
+
+// Error returns a custom string representation of all errors encountered during binding
+func (b BindError) Error() string {
+	e := []string{"Invalid callback request:"}
+	for _, key := range b.Unknown {
+		e = append(e, fmt.Sprintf("unknown parameter '%s'", key))
+	}
+	var malformed []string
+	for key := range b.Malformed {
+		malformed = append(malformed, key)
+	}
+	sort.Strings(malformed)
+	for _, key := range malformed {
+		e = append(e, fmt.Sprintf("malformed parameter '%s': %s", key, b.Malformed[key]))
+	}
+	for _, key := range b.Missing {
+		e = append(e, fmt.Sprintf("missing required parameter '%s'", key))
+	}
+	return strings.Join(e, "\n")
+}
+
+// DefaultBinder is the Binder used by Bind.  It ignores unknown parameters.
+var DefaultBinder = NewBinder()
+
+// Bind will marshal a callback request from the Twilio API
+// into the cbRequest struct provided using the DefaultBinder
+func Bind(cbRequest interface{}, r *http.Request) error {
+	return DefaultBinder.Bind(cbRequest, r)
+}
+
+// RequestValues returns the callback parameters that Bind reads from the request: the query
+// string of GET requests and the form, multipart or flattened JSON body otherwise.
+func RequestValues(r *http.Request) (url.Values, error) {
+	return requestValues(r)
+}
+
+// requestValues returns the callback parameters of the request based on its method
+// and content type
+func requestValues(r *http.Request) (url.Values, error) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+		return r.Form, nil
+	}
+
+	ct := r.Header.Get("Content-Type")
+	if ct == "" {
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+		return r.PostForm, nil
+	}
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return nil, UnsupportedContentTypeError{ct}
+	}
+
+	switch mt {
+	case "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+		return r.PostForm, nil
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(maxMemory); err != nil {
+			return nil, err
+		}
+		return r.PostForm, nil
+	case "application/json":
+		return jsonValues(r)
+	default:
+		return nil, UnsupportedContentTypeError{ct}
+	}
+}
+
+// jsonValues flattens a JSON object body into parameters, using dotted keys for
+// nested objects so they follow the same path notation as form values.  The body is
+// restored so the request can be read again.
+func jsonValues(r *http.Request) (url.Values, error) {
+	values := url.Values{}
+	if r.Body == nil {
+		return values, nil
+	}
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(b))
+	var body map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&body); err != nil {
+		return nil, err
+	}
+	if err := flattenJSON(values, "", body); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func flattenJSON(values url.Values, key string, v interface{}) error {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case string:
+		values.Add(key, t)
+	case json.Number:
+		values.Add(key, t.String())
+	case bool:
+		values.Add(key, strconv.FormatBool(t))
+	case []interface{}:
+		for _, elem := range t {
+			if err := flattenJSON(values, key, elem); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		for k, elem := range t {
+			if key != "" {
+				k = key + "." + k
+			}
+			if err := flattenJSON(values, k, elem); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("Unsupported JSON value for key '%s'", key)
 	}
 	return nil
 }
@@ -2,9 +2,11 @@ package twiml
 
 import (
 	"bytes"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -58,4 +60,97 @@ var _ = Describe("Callback binding", func() {
 		Expect(err).ToNot(HaveOccurred())
 		Expect(vr).To(Equal(exp))
 	})
+
+	It("can bind query parameters of a GET callback", func() {
+		r, _ := http.NewRequest("GET", "https://test.com/?CallSid=testsid&DialCallStatus=busy&DialCallDuration=4", nil)
+		exp := DialActionRequest{
+			VoiceRequest:     VoiceRequest{CallSid: "testsid"},
+			DialCallStatus:   "busy",
+			DialCallDuration: 4,
+		}
+		var dr DialActionRequest
+		err := Bind(&dr, r)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dr).To(Equal(exp))
+	})
+
+	It("can bind a JSON callback body", func() {
+		body := `{"CallSid": "testsid", "RecordingUrl": "https://test.api", "RecordingDuration": 10}`
+		r, _ := http.NewRequest("POST", "https://test.com", bytes.NewBufferString(body))
+		r.Header.Add("Content-Type", "application/json; charset=utf-8")
+		exp := RecordActionRequest{
+			VoiceRequest:      VoiceRequest{CallSid: "testsid"},
+			RecordingURL:      "https://test.api",
+			RecordingDuration: 10,
+		}
+		var rr RecordActionRequest
+		err := Bind(&rr, r)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rr).To(Equal(exp))
+	})
+
+	It("can bind a multipart callback body", func() {
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		w.WriteField("CallSid", "testsid")
+		w.WriteField("From", "+19999999999")
+		w.Close()
+		r, _ := http.NewRequest("POST", "https://test.com", &body)
+		r.Header.Add("Content-Type", w.FormDataContentType())
+		exp := VoiceRequest{
+			CallSid: "testsid",
+			From:    "+19999999999",
+		}
+		var vr VoiceRequest
+		err := Bind(&vr, r)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vr).To(Equal(exp))
+	})
+
+	It("returns a typed error for unsupported content types", func() {
+		r, _ := http.NewRequest("POST", "https://test.com", bytes.NewBufferString("<xml/>"))
+		r.Header.Add("Content-Type", "text/xml")
+		var vr VoiceRequest
+		err := Bind(&vr, r)
+		Expect(err).To(Equal(UnsupportedContentTypeError{"text/xml"}))
+	})
+
+	It("can bind with a configured binder", func() {
+		values := map[string]string{
+			"CallSid": "testsid",
+			"Unknown": "value",
+		}
+		var vr VoiceRequest
+		err := NewBinder(Strict()).Bind(&vr, makeRequest(values))
+		Expect(err).To(HaveOccurred())
+
+		err = NewBinder(AliasTag("twilio")).Bind(&vr, makeRequest(values))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vr.CallSid).To(Equal("testsid"))
+	})
+
+	It("can bind concurrently", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				defer GinkgoRecover()
+				values := map[string]string{
+					"CallSid":           "testsid" + strconv.Itoa(i),
+					"RecordingDuration": strconv.Itoa(i),
+				}
+				var rr RecordActionRequest
+				err := Bind(&rr, makeRequest(values))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rr.RecordingDuration).To(Equal(i))
+
+				var vr VoiceRequest
+				err = NewBinder(ZeroEmpty(true)).Bind(&vr, makeRequest(values))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vr.CallSid).To(Equal(values["CallSid"]))
+			}(i)
+		}
+		wg.Wait()
+	})
 })
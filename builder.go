@@ -0,0 +1,316 @@
+package twiml
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Option sets an attribute of a verb created with a Builder
+type Option func(m Markup) error
+
+// Attr returns an Option setting the attribute with the XML name provided.  The value must be a
+// string, int or bool matching the type of the attribute.
+func Attr(name string, value interface{}) Option {
+	return func(m Markup) error {
+		v := reflect.Indirect(reflect.ValueOf(m))
+		i, ok := markupFields(v.Type()).attrs[name]
+		if !ok {
+			return fmt.Errorf("%s has no attribute '%s'", m.Type(), name)
+		}
+		f := v.Field(i)
+		val := reflect.ValueOf(value)
+		if !val.IsValid() || val.Type() != f.Type() {
+			return fmt.Errorf("%s attribute '%s' must be of type %s, got %T", m.Type(), name, f.Type(), value)
+		}
+		f.Set(val)
+		return nil
+	}
+}
+
+// Options for common attributes follow.  Use Attr for any other attribute.
+
+// Action sets the URL Twilio requests when a verb completes
+func Action(url string) Option {
+	return Attr("action", url)
+}
+
+// Method sets the HTTP method used to request the action URL
+func Method(method string) Option {
+	return Attr("method", method)
+}
+
+// Voice sets the voice of a Say verb
+func Voice(voice string) Option {
+	return Attr("voice", voice)
+}
+
+// Language sets the language of a Say or Gather verb
+func Language(language string) Option {
+	return Attr("language", language)
+}
+
+// Loop sets the number of times a Say or Play verb repeats
+func Loop(n int) Option {
+	return Attr("loop", n)
+}
+
+// Timeout sets the number of seconds to wait before a verb times out
+func Timeout(seconds int) Option {
+	return Attr("timeout", seconds)
+}
+
+// NumDigits sets the number of digits a Gather verb collects
+func NumDigits(n int) Option {
+	return Attr("numDigits", n)
+}
+
+// FinishOnKey sets the key that ends input to a Gather or Record verb
+func FinishOnKey(key string) Option {
+	return Attr("finishOnKey", key)
+}
+
+// Input sets the input types of a Gather verb, such as "dtmf speech"
+func Input(input string) Option {
+	return Attr("input", input)
+}
+
+// Length sets the number of seconds of a Pause verb
+func Length(seconds int) Option {
+	return Attr("length", seconds)
+}
+
+// MaxLength sets the maximum number of seconds of a Record verb
+func MaxLength(seconds int) Option {
+	return Attr("maxLength", seconds)
+}
+
+// Transcribe requests a transcription of a Record verb
+func Transcribe(t bool) Option {
+	return Attr("transcribe", t)
+}
+
+// CallerID sets the caller ID of a Dial verb
+func CallerID(number string) Option {
+	return Attr("callerId", number)
+}
+
+// TimeLimit sets the maximum number of seconds of a Dial verb
+func TimeLimit(seconds int) Option {
+	return Attr("timeLimit", seconds)
+}
+
+// Reason sets the reason given by a Reject verb
+func Reason(reason string) Option {
+	return Attr("reason", reason)
+}
+
+// URL sets the URL of a Dial noun, such as the screening TwiML of a Number
+func URL(url string) Option {
+	return Attr("url", url)
+}
+
+// StatusCallback sets the URL Twilio requests with status changes of a verb
+func StatusCallback(url string) Option {
+	return Attr("statusCallback", url)
+}
+
+// Builder constructs a Response by chaining verbs.  Errors from options and validation are
+// collected and returned by Build.
+type Builder struct {
+	res  *Response
+	errs []error
+}
+
+// New creates a Builder for a new response
+func New() *Builder {
+	return &Builder{res: NewResponse()}
+}
+
+// apply sets the options on the markup, collecting any errors
+func (b *Builder) apply(m Markup, opts []Option) Markup {
+	for _, opt := range opts {
+		if err := opt(m); err != nil {
+			b.errs = append(b.errs, err)
+		}
+	}
+	return m
+}
+
+// split separates options from the function building nested markup
+func (b *Builder) split(verb string, args []interface{}) ([]Option, []interface{}) {
+	var opts []Option
+	var fns []interface{}
+	for _, arg := range args {
+		switch a := arg.(type) {
+		case Option:
+			opts = append(opts, a)
+		case func(Markup) error:
+			opts = append(opts, a)
+		case func(*GatherBuilder), func(*DialBuilder):
+			fns = append(fns, a)
+		default:
+			b.errs = append(b.errs, fmt.Errorf("Unsupported argument of type %T to %s", arg, verb))
+		}
+	}
+	return opts, fns
+}
+
+// Add appends verbs constructed without the builder
+func (b *Builder) Add(ml ...Markup) *Builder {
+	b.res.Add(ml...)
+	return b
+}
+
+// Say speaks the text
+func (b *Builder) Say(text string, opts ...Option) *Builder {
+	return b.Add(b.apply(&Say{Text: text}, opts))
+}
+
+// Play plays the audio file at the URL
+func (b *Builder) Play(url string, opts ...Option) *Builder {
+	return b.Add(b.apply(&Play{URL: url}, opts))
+}
+
+// Pause waits silently
+func (b *Builder) Pause(opts ...Option) *Builder {
+	return b.Add(b.apply(&Pause{}, opts))
+}
+
+// Gather collects input from the caller.  Arguments are Options and an optional
+// func(*GatherBuilder) adding the nested verbs.
+func (b *Builder) Gather(args ...interface{}) *Builder {
+	g := &Gather{}
+	opts, fns := b.split("Gather", args)
+	b.apply(g, opts)
+	for _, fn := range fns {
+		if f, ok := fn.(func(*GatherBuilder)); ok {
+			f(&GatherBuilder{b: b, g: g})
+		} else {
+			b.errs = append(b.errs, fmt.Errorf("Unsupported argument of type %T to Gather", fn))
+		}
+	}
+	return b.Add(g)
+}
+
+// Dial connects the caller to the number.  Arguments are Options and an optional
+// func(*DialBuilder) adding nested nouns, in which case number should be empty.
+func (b *Builder) Dial(number string, args ...interface{}) *Builder {
+	d := &Dial{Number: number}
+	opts, fns := b.split("Dial", args)
+	b.apply(d, opts)
+	for _, fn := range fns {
+		if f, ok := fn.(func(*DialBuilder)); ok {
+			f(&DialBuilder{b: b, d: d})
+		} else {
+			b.errs = append(b.errs, fmt.Errorf("Unsupported argument of type %T to Dial", fn))
+		}
+	}
+	return b.Add(d)
+}
+
+// Record records the caller
+func (b *Builder) Record(opts ...Option) *Builder {
+	return b.Add(b.apply(&Record{}, opts))
+}
+
+// Redirect transfers control of the call to the TwiML at the URL
+func (b *Builder) Redirect(url string, opts ...Option) *Builder {
+	return b.Add(b.apply(&Redirect{URL: url}, opts))
+}
+
+// Enqueue places the caller in the queue
+func (b *Builder) Enqueue(queue string, opts ...Option) *Builder {
+	return b.Add(b.apply(&Enqueue{QueueName: queue}, opts))
+}
+
+// Message sends a message with the text
+func (b *Builder) Message(text string, opts ...Option) *Builder {
+	return b.Add(b.apply(&Sms{Text: text}, opts))
+}
+
+// Reject rejects the call without answering it
+func (b *Builder) Reject(opts ...Option) *Builder {
+	return b.Add(b.apply(&Reject{}, opts))
+}
+
+// Hangup ends the call
+func (b *Builder) Hangup() *Builder {
+	return b.Add(&Hangup{})
+}
+
+// Leave removes the caller from a queue
+func (b *Builder) Leave() *Builder {
+	return b.Add(&Leave{})
+}
+
+// Build returns the response, or a ValidationError with every option error and validation error
+func (b *Builder) Build() (*Response, error) {
+	errs := append([]error{}, b.errs...)
+	if err := b.res.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return b.res, ValidationError{errs}
+	}
+	return b.res, nil
+}
+
+// GatherBuilder adds the verbs nested in a Gather
+type GatherBuilder struct {
+	b *Builder
+	g *Gather
+}
+
+// Say speaks the text while gathering input
+func (gb *GatherBuilder) Say(text string, opts ...Option) *GatherBuilder {
+	gb.g.Add(gb.b.apply(&Say{Text: text}, opts))
+	return gb
+}
+
+// Play plays the audio file at the URL while gathering input
+func (gb *GatherBuilder) Play(url string, opts ...Option) *GatherBuilder {
+	gb.g.Add(gb.b.apply(&Play{URL: url}, opts))
+	return gb
+}
+
+// Pause waits silently while gathering input
+func (gb *GatherBuilder) Pause(opts ...Option) *GatherBuilder {
+	gb.g.Add(gb.b.apply(&Pause{}, opts))
+	return gb
+}
+
+// DialBuilder adds the nouns nested in a Dial
+type DialBuilder struct {
+	b *Builder
+	d *Dial
+}
+
+// Number dials a phone number
+func (db *DialBuilder) Number(number string, opts ...Option) *DialBuilder {
+	db.d.Add(db.b.apply(&Number{Number: number}, opts))
+	return db
+}
+
+// Client dials a Twilio Client
+func (db *DialBuilder) Client(name string, opts ...Option) *DialBuilder {
+	db.d.Add(db.b.apply(&Client{Name: name}, opts))
+	return db
+}
+
+// Sip dials a SIP address
+func (db *DialBuilder) Sip(address string, opts ...Option) *DialBuilder {
+	db.d.Add(db.b.apply(&Sip{Address: address}, opts))
+	return db
+}
+
+// Conference connects the caller to a conference room
+func (db *DialBuilder) Conference(name string, opts ...Option) *DialBuilder {
+	db.d.Add(db.b.apply(&Conference{ConferenceName: name}, opts))
+	return db
+}
+
+// Queue connects the caller to the call at the front of a queue
+func (db *DialBuilder) Queue(name string, opts ...Option) *DialBuilder {
+	db.d.Add(db.b.apply(&Queue{Name: name}, opts))
+	return db
+}
@@ -0,0 +1,51 @@
+package twiml
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Building responses", func() {
+	It("chains verbs and options", func() {
+		res, err := New().
+			Say("Hi", Voice(Alice), Language(EnglishUK)).
+			Gather(NumDigits(1), Action("/menu"), func(g *GatherBuilder) {
+				g.Say("Press 1").Pause(Length(1))
+			}).
+			Dial("", Timeout(15), func(d *DialBuilder) {
+				d.Number("+15555555555", URL("/whisper")).Client("support")
+			}).
+			Hangup().
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+
+		exp := NewResponse()
+		g := &Gather{NumDigits: 1, Action: "/menu"}
+		g.Add(&Say{Text: "Press 1"}, &Pause{Length: 1})
+		d := &Dial{Timeout: 15}
+		d.Add(&Number{Number: "+15555555555", URL: "/whisper"}, &Client{Name: "support"})
+		exp.Add(&Say{Text: "Hi", Voice: Alice, Language: EnglishUK}, g, d, &Hangup{})
+		Expect(res).To(Equal(exp))
+	})
+
+	It("sets any attribute with Attr", func() {
+		res, err := New().Record(Attr("playBeep", true), MaxLength(30)).Build()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Children).To(Equal([]Markup{&Record{PlayBeep: true, MaxLength: 30}}))
+	})
+
+	It("surfaces option and validation errors at Build", func() {
+		_, err := New().
+			Gather(Voice(Alice)).
+			Say("Hi", Attr("loop", "twice")).
+			Redirect("/next", Method("PUT")).
+			Dial("", "not an option").
+			Build()
+		Expect(err).To(HaveOccurred())
+		verr, ok := err.(ValidationError)
+		Expect(ok).To(BeTrue())
+		Expect(verr.Errors).To(HaveLen(4))
+		Expect(verr.Errors[0].Error()).To(Equal("Gather has no attribute 'voice'"))
+		Expect(verr.Errors[1].Error()).To(Equal("Say attribute 'loop' must be of type int, got string"))
+	})
+})
@@ -1,17 +1,22 @@
 package twiml
 
+// Callback request fields tagged `twiml:"required"` are always sent by the Twilio API.  A Strict
+// Binder reports a BindError when they are missing.
+
 // VoiceRequest represents the standard request format for callbacks received from the Twilio API.  This struct is
 // embedded in other callback requests that return this common data format.
 type VoiceRequest struct {
-	CallSid       string
-	AccountSid    string
-	From          string
-	To            string
-	CallStatus    string
-	APIVersion    string `schema:"ApiVersion"`
-	Direction     string
+	CallSid       string `twiml:"required"`
+	AccountSid    string `twiml:"required"`
+	From          string `twiml:"required"`
+	To            string `twiml:"required"`
+	CallStatus    string `twiml:"required"`
+	APIVersion    string `schema:"ApiVersion" twiml:"required"`
+	Direction     string `twiml:"required"`
 	ForwardedFrom string
 	CallerName    string
+	ParentCallSid string
+	CallToken     string
 	FromCity      string
 	FromState     string
 	FromZip       string
@@ -20,14 +25,25 @@ type VoiceRequest struct {
 	ToState       string
 	ToZip         string
 	ToCountry     string
+	Called        string
+	CalledCity    string
+	CalledState   string
+	CalledZip     string
+	CalledCountry string
+	Caller        string
+	CallerCity    string
+	CallerState   string
+	CallerZip     string
+	CallerCountry string
 }
 
 // DialActionRequest represents a request as a result of declaring an `action` URL on the Dial verb
 type DialActionRequest struct {
 	VoiceRequest
-	DialCallStatus        string
+	DialCallStatus        string `twiml:"required"`
 	DialCallSid           string
 	DialCallDuration      int
+	DialBridged           bool
 	RecordingURL          string `schema:"RecordingUrl"`
 	QueueSid              string
 	DequeueResult         string
@@ -36,10 +52,20 @@ type DialActionRequest struct {
 	DequeuedCallDuration  int
 }
 
+// GatherActionRequest represents a request as a result of declaring an `action` URL on
+// the Gather verb
+type GatherActionRequest struct {
+	VoiceRequest
+	Digits       string
+	SpeechResult string
+	Confidence   float64
+}
+
 // RecordActionRequest represents a request as a result of declaring an `action`
 // URL on a Record verb
 type RecordActionRequest struct {
 	VoiceRequest
+	RecordingSid      string
 	RecordingURL      string `schema:"RecordingUrl"`
 	RecordingDuration int
 	Digits            string
@@ -48,27 +74,30 @@ type RecordActionRequest struct {
 // RecordingStatusCallbackRequest represents a request as a result of declaring
 // a `recordingStatusCallback` on a Record verb
 type RecordingStatusCallbackRequest struct {
-	AccountSid        string
-	CallSid           string
-	RecordingSid      string
-	RecordingURL      string `schema:"RecordingUrl"`
-	RecordingStatus   string
-	RecordingDuration int
-	RecordingChannels int
-	RecordingSource   string
+	AccountSid         string `twiml:"required"`
+	CallSid            string `twiml:"required"`
+	RecordingSid       string `twiml:"required"`
+	RecordingURL       string `schema:"RecordingUrl" twiml:"required"`
+	RecordingStatus    string `twiml:"required"`
+	RecordingDuration  int
+	RecordingChannels  int
+	RecordingStartTime string
+	RecordingSource    string
+	RecordingTrack     string
+	ErrorCode          int
 }
 
 // TranscribeCallbackRequest represents a request as a result of declaring
 // a `transcribeCallback` on a Record verb
 type TranscribeCallbackRequest struct {
-	TranscriptionSid    string
+	TranscriptionSid    string `twiml:"required"`
 	TranscriptionText   string
-	TranscriptionStatus string
+	TranscriptionStatus string `twiml:"required"`
 	TranscriptionURL    string `schema:"TranscriptionUrl"`
-	RecordingSid        string
+	RecordingSid        string `twiml:"required"`
 	RecordingURL        string `schema:"RecordingUrl"`
-	CallSid             string
-	AccountSid          string
+	CallSid             string `twiml:"required"`
+	AccountSid          string `twiml:"required"`
 	From                string
 	To                  string
 	CallStatus          string
@@ -0,0 +1,147 @@
+package twiml
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// voiceParams are the parameters Twilio sends with every voice callback
+var voiceParams = map[string]string{
+	"CallSid":       "CA00000000000000000000000000000000",
+	"AccountSid":    "AC00000000000000000000000000000000",
+	"From":          "+19999999999",
+	"To":            "+19991111111",
+	"CallStatus":    "in-progress",
+	"ApiVersion":    "2010-04-01",
+	"Direction":     "inbound",
+	"CallerName":    "",
+	"CallToken":     "token",
+	"FromCity":      "SAN FRANCISCO",
+	"FromState":     "CA",
+	"FromZip":       "94105",
+	"FromCountry":   "US",
+	"ToCity":        "",
+	"ToState":       "NY",
+	"ToZip":         "",
+	"ToCountry":     "US",
+	"Called":        "+19991111111",
+	"CalledCity":    "",
+	"CalledState":   "NY",
+	"CalledZip":     "",
+	"CalledCountry": "US",
+	"Caller":        "+19999999999",
+	"CallerCity":    "SAN FRANCISCO",
+	"CallerState":   "CA",
+	"CallerZip":     "94105",
+	"CallerCountry": "US",
+}
+
+func withVoiceParams(v map[string]string) map[string]string {
+	params := make(map[string]string)
+	for key, value := range voiceParams {
+		params[key] = value
+	}
+	for key, value := range v {
+		params[key] = value
+	}
+	return params
+}
+
+var _ = Describe("Callback request contracts", func() {
+	binder := NewBinder(Strict())
+
+	It("binds every parameter of a voice request", func() {
+		var vr VoiceRequest
+		err := binder.Bind(&vr, makeRequest(voiceParams))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("binds every parameter of a dial action request", func() {
+		var dr DialActionRequest
+		err := binder.Bind(&dr, makeRequest(withVoiceParams(map[string]string{
+			"DialCallStatus":   "completed",
+			"DialCallSid":      "CA11111111111111111111111111111111",
+			"DialCallDuration": "42",
+			"DialBridged":      "true",
+			"RecordingUrl":     "https://api.twilio.com/recording",
+		})))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dr.DialBridged).To(BeTrue())
+	})
+
+	It("binds every parameter of a record action request", func() {
+		var rr RecordActionRequest
+		err := binder.Bind(&rr, makeRequest(withVoiceParams(map[string]string{
+			"RecordingSid":      "RE00000000000000000000000000000000",
+			"RecordingUrl":      "https://api.twilio.com/recording",
+			"RecordingDuration": "12",
+			"Digits":            "hangup",
+		})))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("binds every parameter of a recording status callback", func() {
+		var rr RecordingStatusCallbackRequest
+		err := binder.Bind(&rr, makeRequest(map[string]string{
+			"AccountSid":         "AC00000000000000000000000000000000",
+			"CallSid":            "CA00000000000000000000000000000000",
+			"RecordingSid":       "RE00000000000000000000000000000000",
+			"RecordingUrl":       "https://api.twilio.com/recording",
+			"RecordingStatus":    "completed",
+			"RecordingDuration":  "12",
+			"RecordingChannels":  "1",
+			"RecordingStartTime": "Mon, 22 Aug 2011 17:25:13 +0000",
+			"RecordingSource":    "RecordVerb",
+			"RecordingTrack":     "both",
+		}))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("binds every parameter of a transcription callback", func() {
+		var tr TranscribeCallbackRequest
+		err := binder.Bind(&tr, makeRequest(map[string]string{
+			"TranscriptionSid":    "TR00000000000000000000000000000000",
+			"TranscriptionText":   "hello",
+			"TranscriptionStatus": "completed",
+			"TranscriptionUrl":    "https://api.twilio.com/transcription",
+			"RecordingSid":        "RE00000000000000000000000000000000",
+			"RecordingUrl":        "https://api.twilio.com/recording",
+			"CallSid":             "CA00000000000000000000000000000000",
+			"AccountSid":          "AC00000000000000000000000000000000",
+			"From":                "+19999999999",
+			"To":                  "+19991111111",
+			"CallStatus":          "completed",
+			"ApiVersion":          "2010-04-01",
+			"Direction":           "inbound",
+			"ForwardedFrom":       "",
+		}))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("reports unknown, malformed and missing parameters", func() {
+		var rr RecordActionRequest
+		err := binder.Bind(&rr, makeRequest(map[string]string{
+			"CallSid":           "CA00000000000000000000000000000000",
+			"RecordingLink":     "https://api.twilio.com/recording",
+			"RecordingDuration": "twelve",
+		}))
+		Expect(err).To(BeAssignableToTypeOf(BindError{}))
+		bindErr := err.(BindError)
+		Expect(bindErr.Unknown).To(Equal([]string{"RecordingLink"}))
+		Expect(bindErr.Malformed).To(HaveKey("RecordingDuration"))
+		Expect(bindErr.Missing).To(Equal([]string{"AccountSid", "ApiVersion", "CallStatus", "Direction", "From", "To"}))
+	})
+
+	It("only reports malformed parameters when not strict", func() {
+		var rr RecordActionRequest
+		err := Bind(&rr, makeRequest(map[string]string{
+			"RecordingURL":      "https://api.twilio.com/recording",
+			"RecordingDuration": "twelve",
+		}))
+		Expect(err).To(BeAssignableToTypeOf(BindError{}))
+		bindErr := err.(BindError)
+		Expect(bindErr.Unknown).To(BeEmpty())
+		Expect(bindErr.Missing).To(BeEmpty())
+		Expect(bindErr.Malformed).To(HaveKey("RecordingDuration"))
+	})
+})
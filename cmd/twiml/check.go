@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/BTBurke/twiml"
+)
+
+func validateCmd(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	inputs, err := readInputs(flags.Args(), stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "twiml: %s\n", err)
+		return 2
+	}
+
+	status := 0
+	for _, in := range inputs {
+		res, ok := decode(in, stdout)
+		if !ok {
+			status = 1
+			continue
+		}
+		lines := positions(in.data)
+		for _, p := range validate(res) {
+			fmt.Fprintf(stdout, "%s:%d: %s: %s\n", in.name, lines[p.path], displayPath(p.path), p.err)
+			status = 1
+		}
+		if max := twiml.DefaultLimits.MaxBytes; max > 0 && len(in.data) > max {
+			// Response.Validate leaves the size to the encoder, so check the document as written
+			err := twiml.LimitError{Limit: "MaxBytes", Max: max, Actual: len(in.data)}
+			fmt.Fprintf(stdout, "%s:1: %s\n", in.name, err)
+			status = 1
+		}
+	}
+	return status
+}
+
+func lintCmd(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	strict := flags.Bool("strict", false, "exit with status 1 on warnings as well as errors")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	inputs, err := readInputs(flags.Args(), stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "twiml: %s\n", err)
+		return 2
+	}
+
+	status := 0
+	for _, in := range inputs {
+		res, ok := decode(in, stdout)
+		if !ok {
+			status = 1
+			continue
+		}
+		lines := positions(in.data)
+		for _, f := range twiml.Lint(res) {
+			fmt.Fprintf(stdout, "%s:%d: %s\n", in.name, lines[f.Path], f)
+			if f.Severity == twiml.SeverityError || (*strict && f.Severity == twiml.SeverityWarning) {
+				status = 1
+			}
+		}
+	}
+	return status
+}
+
+// decode decodes the input, reporting a decode error with its location
+func decode(in input, stdout io.Writer) (*twiml.Response, bool) {
+	res, err := twiml.Decode(in.data)
+	switch e := err.(type) {
+	case nil:
+		return res, true
+	case twiml.DecodeError:
+		fmt.Fprintf(stdout, "%s:%d: %s\n", in.name, e.Line, e.Msg)
+	case *xml.SyntaxError:
+		fmt.Fprintf(stdout, "%s:%d: %s\n", in.name, e.Line, e.Msg)
+	default:
+		fmt.Fprintf(stdout, "%s: %s\n", in.name, err)
+	}
+	return nil, false
+}
+
+// problem is a validation error of the verb at path
+type problem struct {
+	path string
+	err  error
+}
+
+// validate validates every verb in the response and returns the errors of the innermost
+// failing verbs, since a verb that nests markup fails whenever any of its children fail
+func validate(res *twiml.Response) []problem {
+	var failed []problem
+	twiml.Walk(res, func(path string, m twiml.Markup) error {
+		if err := m.Validate(); err != nil {
+			failed = append(failed, problem{path, err})
+		}
+		return nil
+	})
+
+	var innermost []problem
+	for i, p := range failed {
+		nested := false
+		for _, q := range failed[i+1:] {
+			if p.path == "" || strings.HasPrefix(q.path, p.path+"/") {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			innermost = append(innermost, p)
+		}
+	}
+	return innermost
+}
+
+// positions maps the path of each element, as reported by twiml.Walk, to its line in the document
+func positions(data []byte) map[string]int {
+	type frame struct {
+		path string
+		seen map[string]int
+	}
+	lines := make(map[string]int)
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var stack []frame
+	var off int64
+	line := 1
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return lines
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			next := d.InputOffset()
+			line += bytes.Count(data[off:next], []byte("\n"))
+			off = next
+			path := ""
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				path = fmt.Sprintf("%s[%d]", t.Name.Local, parent.seen[t.Name.Local])
+				if parent.path != "" {
+					path = parent.path + "/" + path
+				}
+				parent.seen[t.Name.Local]++
+			}
+			lines[path] = line
+			stack = append(stack, frame{path: path, seen: make(map[string]int)})
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "Response"
+	}
+	return path
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/BTBurke/twiml"
+)
+
+func fmtCmd(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	list := flags.Bool("l", false, "list files whose formatting differs from canonical form")
+	write := flags.Bool("w", false, "write the canonical form back to each file")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	inputs, err := readInputs(flags.Args(), stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "twiml: %s\n", err)
+		return 2
+	}
+
+	status := 0
+	for _, in := range inputs {
+		res, ok := decode(in, stdout)
+		if !ok {
+			status = 1
+			continue
+		}
+		// formatting does not judge the markup, which is left to validate
+		res.Validation = twiml.ValidateOff
+		b, err := res.Encode()
+		if err != nil {
+			fmt.Fprintf(stdout, "%s: %s\n", in.name, err)
+			status = 1
+			continue
+		}
+		b = append(b, '\n')
+
+		changed := !bytes.Equal(b, in.data)
+		if *list && changed {
+			fmt.Fprintln(stdout, in.name)
+		}
+		if *write && changed && in.name != "<stdin>" {
+			if err := ioutil.WriteFile(in.name, b, 0644); err != nil {
+				fmt.Fprintf(stderr, "twiml: %s\n", err)
+				return 2
+			}
+		}
+		if !*list && !*write {
+			stdout.Write(b)
+		}
+	}
+	return status
+}
@@ -0,0 +1,88 @@
+// Command twiml checks and formats TwiML documents.
+//
+// Usage:
+//
+//	twiml validate [file ...]
+//	twiml lint [-strict] [file ...]
+//	twiml fmt [-l] [-w] [file ...]
+//	twiml serve [-addr addr] [-log file] (-dir dir | -proxy url)
+//	twiml replay [-log file] [-target url] [-n index] [-auth-token token]
+//
+// The validate, lint and fmt commands read standard input when no files are given.  Problems
+// are reported as file:line: message and the command exits with status 1 when any are found.
+//
+// The serve command hosts TwiML from a directory or proxies to a TwiML application, appending
+// every request and the TwiML returned to a JSONL log.  The replay command resends logged
+// requests to a new build and reports how its TwiML differs from the captured response.
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+const usage = `usage: twiml <command> [flags] [file ...]
+
+Commands:
+  validate  check that TwiML decodes and passes validation
+  lint      report TwiML that is valid but unlikely to behave as intended
+  fmt       print TwiML in canonical form
+  serve     host TwiML and log every request and response
+  replay    resend logged requests and diff the TwiML returned
+`
+
+// command runs a subcommand and returns the exit status
+type command func(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int
+
+var commands = map[string]command{
+	"validate": validateCmd,
+	"lint":     lintCmd,
+	"fmt":      fmtCmd,
+	"serve":    serveCmd,
+	"replay":   replayCmd,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "twiml: unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+	return cmd(args[1:], stdin, stdout, stderr)
+}
+
+// input is a TwiML document read from a file or standard input
+type input struct {
+	name string
+	data []byte
+}
+
+// readInputs reads each named file, or standard input when no files are named
+func readInputs(names []string, stdin io.Reader) ([]input, error) {
+	if len(names) == 0 {
+		b, err := ioutil.ReadAll(stdin)
+		if err != nil {
+			return nil, err
+		}
+		return []input{{name: "<stdin>", data: b}}, nil
+	}
+	inputs := make([]input, 0, len(names))
+	for _, name := range names {
+		b, err := ioutil.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, input{name: name, data: b})
+	}
+	return inputs, nil
+}
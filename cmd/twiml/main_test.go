@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const messy = `<Response>
+<Gather numDigits="1"   action="/menu"><Say voice="robot">Press 1</Say></Gather>
+    <Hangup/>
+  <Say>Goodbye</Say>
+</Response>`
+
+// tempDir creates a directory that is removed by calling cleanup
+func tempDir(t *testing.T) (dir string, cleanup func()) {
+	dir, err := ioutil.TempDir("", "twiml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func runCmd(stdin string, args ...string) (int, string) {
+	var stdout, stderr bytes.Buffer
+	status := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return status, stdout.String() + stderr.String()
+}
+
+func TestValidate(t *testing.T) {
+	status, out := runCmd(messy, "validate")
+	assert.Equal(t, 1, status)
+	assert.Equal(t, "<stdin>:2: Gather[0]/Say[0]: Say markup failed validation\n", out)
+
+	status, out = runCmd(`<Response><Hangup/></Response>`, "validate")
+	assert.Equal(t, 0, status)
+	assert.Empty(t, out)
+}
+
+func TestValidateSize(t *testing.T) {
+	large := "<Response>" + strings.Repeat("<Say>"+strings.Repeat("a", 4000)+"</Say>", 20) + "</Response>"
+	status, out := runCmd(large, "validate")
+	assert.Equal(t, 1, status)
+	assert.Equal(t, "<stdin>:1: Response: response is 80241 bytes, over the limit of 65536\n", out)
+}
+
+func TestValidateDecodeErrors(t *testing.T) {
+	status, out := runCmd("<Response>\n<Shout/>\n</Response>", "validate")
+	assert.Equal(t, 1, status)
+	assert.Equal(t, "<stdin>:2: Unknown TwiML verb 'Shout'\n", out)
+
+	status, out = runCmd("<Response>\n<Say>\n</Response>", "validate")
+	assert.Equal(t, 1, status)
+	assert.Contains(t, out, "<stdin>:3: ")
+}
+
+func TestLint(t *testing.T) {
+	status, out := runCmd(messy, "lint")
+	assert.Equal(t, 0, status)
+	assert.Equal(t, "<stdin>:4: warning: Say[0]: Say is unreachable after Hangup (unreachable)\n", out)
+
+	status, _ = runCmd(messy, "lint", "-strict")
+	assert.Equal(t, 1, status)
+}
+
+func TestFmt(t *testing.T) {
+	valid := `<Response><Gather numDigits="1"   action="/menu"><Say>Press 1</Say></Gather></Response>`
+	status, out := runCmd(valid, "fmt")
+	assert.Equal(t, 0, status)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<Response>
+  <Gather action="/menu" numDigits="1">
+    <Say>Press 1</Say>
+  </Gather>
+</Response>
+`, out)
+
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	path := filepath.Join(dir, "menu.xml")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(valid), 0644))
+	status, out = runCmd("", "fmt", "-l", "-w", path)
+	assert.Equal(t, 0, status)
+	assert.Equal(t, path+"\n", out)
+
+	status, out = runCmd("", "fmt", "-l", path)
+	assert.Equal(t, 0, status)
+	assert.Empty(t, out)
+
+	status, out = runCmd(messy, "fmt")
+	assert.Equal(t, 0, status)
+	assert.Contains(t, out, `<Say voice="robot">Press 1</Say>`)
+}
+
+func TestUsage(t *testing.T) {
+	status, _ := runCmd("", "bogus")
+	assert.Equal(t, 2, status)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/BTBurke/twiml"
+)
+
+func replayCmd(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	logPath := flags.String("log", "twiml.jsonl", "log of requests captured by serve")
+	target := flags.String("target", "http://localhost:8080", "base URL of the TwiML application to replay requests against")
+	n := flags.Int("n", -1, "replay only the request on this line of the log, counting from 0")
+	authToken := flags.String("auth-token", "", "re-sign replayed GET and form requests for the target URL with this auth token")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	base, err := url.Parse(*target)
+	if err != nil {
+		fmt.Fprintf(stderr, "twiml: invalid target URL: %s\n", err)
+		return 2
+	}
+	f, err := os.Open(*logPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "twiml: %s\n", err)
+		return 2
+	}
+	defer f.Close()
+	entries, err := readLog(f)
+	if err != nil {
+		fmt.Fprintf(stderr, "twiml: %s\n", err)
+		return 2
+	}
+
+	indexes := make([]int, 0, len(entries))
+	switch {
+	case *n >= len(entries):
+		fmt.Fprintf(stderr, "twiml: log has %d requests\n", len(entries))
+		return 2
+	case *n >= 0:
+		indexes = append(indexes, *n)
+	default:
+		for i := range entries {
+			indexes = append(indexes, i)
+		}
+	}
+
+	status := 0
+	for _, i := range indexes {
+		e := entries[i]
+		changes, err := replay(http.DefaultClient, base, e, *authToken)
+		switch {
+		case err != nil:
+			fmt.Fprintf(stdout, "#%d %s %s: %s\n", i, e.Method, e.URL, err)
+			status = 1
+		case len(changes) == 0:
+			fmt.Fprintf(stdout, "#%d %s %s: unchanged\n", i, e.Method, e.URL)
+		default:
+			fmt.Fprintf(stdout, "#%d %s %s: %d changes\n", i, e.Method, e.URL, len(changes))
+			for _, c := range changes {
+				fmt.Fprintf(stdout, "    %s\n", c)
+			}
+			status = 1
+		}
+	}
+	return status
+}
+
+// replay resends a captured request to the target and diffs the TwiML returned against the
+// captured response
+func replay(client *http.Client, base *url.URL, e entry, authToken string) ([]twiml.Change, error) {
+	ref, err := url.Parse(e.URL)
+	if err != nil {
+		return nil, err
+	}
+	u := base.ResolveReference(ref).String()
+	req, err := http.NewRequest(e.Method, u, strings.NewReader(e.Body))
+	if err != nil {
+		return nil, err
+	}
+	if e.ContentType != "" {
+		req.Header.Set("Content-Type", e.ContentType)
+	}
+	switch {
+	case authToken != "" && e.Method == http.MethodGet:
+		req.Header.Set(twiml.SignatureHeader, twiml.Signature(authToken, u, nil))
+	case authToken != "":
+		params, err := signedParams(ref, e)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(twiml.SignatureHeader, twiml.Signature(authToken, u, params))
+	case e.Signature != "":
+		req.Header.Set(twiml.SignatureHeader, e.Signature)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != e.Status {
+		return nil, fmt.Errorf("status %d, captured %d", resp.StatusCode, e.Status)
+	}
+
+	captured, err := decodeBody(e.Response)
+	if err != nil {
+		return nil, fmt.Errorf("captured response: %s", err)
+	}
+	replayed, err := decodeBody(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("replayed response: %s", err)
+	}
+	return twiml.Diff(captured, replayed), nil
+}
+
+// signedParams returns the parameters of a form body that Twilio signs along with the URL.
+// The captured parameters hold the query values of each key before the body values.
+func signedParams(ref *url.URL, e entry) (url.Values, error) {
+	mt, _, _ := mime.ParseMediaType(e.ContentType)
+	if mt != "application/x-www-form-urlencoded" {
+		return nil, fmt.Errorf("can not sign a request with content type %q", e.ContentType)
+	}
+	query := ref.Query()
+	params := make(url.Values)
+	for k, v := range e.Params {
+		if n := len(query[k]); n < len(v) {
+			params[k] = v[n:]
+		}
+	}
+	return params, nil
+}
+
+// decodeBody decodes a TwiML response body, treating an empty body as a response without verbs
+func decodeBody(body string) (*twiml.Response, error) {
+	if strings.TrimSpace(body) == "" {
+		return twiml.NewResponse(), nil
+	}
+	return twiml.Decode([]byte(body))
+}
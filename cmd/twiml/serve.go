@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BTBurke/twiml"
+)
+
+// entry is a request and the TwiML returned for it, stored as one line of the JSONL log
+type entry struct {
+	Time        time.Time          `json:"time"`
+	Method      string             `json:"method"`
+	URL         string             `json:"url"`
+	ContentType string             `json:"contentType,omitempty"`
+	Signature   string             `json:"signature,omitempty"`
+	Body        string             `json:"body,omitempty"`
+	Params      url.Values         `json:"params,omitempty"`
+	Call        twiml.VoiceRequest `json:"call"`
+	Status      int                `json:"status"`
+	Response    string             `json:"response"`
+}
+
+func serveCmd(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	logPath := flags.String("log", "twiml.jsonl", "file to append captured requests to")
+	dir := flags.String("dir", "", "serve TwiML files from this directory, /path is served from path.xml")
+	proxy := flags.String("proxy", "", "forward requests to the TwiML application at this URL")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	var h http.Handler
+	switch {
+	case *dir != "" && *proxy == "":
+		h = dirHandler(*dir)
+	case *proxy != "" && *dir == "":
+		target, err := url.Parse(*proxy)
+		if err != nil {
+			fmt.Fprintf(stderr, "twiml: invalid proxy URL: %s\n", err)
+			return 2
+		}
+		h = httputil.NewSingleHostReverseProxy(target)
+	default:
+		fmt.Fprintln(stderr, "twiml: serve requires exactly one of -dir or -proxy")
+		return 2
+	}
+
+	f, err := os.OpenFile(*logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		fmt.Fprintf(stderr, "twiml: %s\n", err)
+		return 2
+	}
+	defer f.Close()
+
+	fmt.Fprintf(stdout, "serving TwiML on http://%s, logging to %s\n", *addr, *logPath)
+	if err := http.ListenAndServe(*addr, record(h, f)); err != nil {
+		fmt.Fprintf(stderr, "twiml: %s\n", err)
+		return 1
+	}
+	return 0
+}
+
+// dirHandler serves the TwiML file for each request path, using index.xml for directories
+func dirHandler(dir string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := path.Clean("/" + r.URL.Path)
+		if strings.HasSuffix(r.URL.Path, "/") {
+			p = path.Join(p, "index")
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(p)+".xml"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write(b)
+	})
+}
+
+// capture records the status and body written by a handler
+type capture struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (c *capture) WriteHeader(status int) {
+	c.status = status
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *capture) Write(b []byte) (int, error) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	c.body.Write(b)
+	return c.ResponseWriter.Write(b)
+}
+
+// record wraps a handler, appending every request and the response to the log as JSON lines
+func record(h http.Handler, log io.Writer) http.Handler {
+	var mu sync.Mutex
+	enc := json.NewEncoder(log)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, http.StatusText(400), 400)
+			return
+		}
+		e := entry{
+			Time:        time.Now().UTC(),
+			Method:      r.Method,
+			URL:         r.URL.RequestURI(),
+			ContentType: r.Header.Get("Content-Type"),
+			Signature:   r.Header.Get(twiml.SignatureHeader),
+			Body:        string(body),
+		}
+
+		bound := r.Clone(r.Context())
+		bound.Body = ioutil.NopCloser(bytes.NewReader(body))
+		twiml.Bind(&e.Call, bound)
+		e.Params = params(r, body)
+
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		c := &capture{ResponseWriter: w}
+		h.ServeHTTP(c, r)
+		if c.status == 0 {
+			// a handler that writes nothing responds with an implicit 200
+			c.status = http.StatusOK
+		}
+		e.Status = c.status
+		e.Response = c.body.String()
+
+		mu.Lock()
+		defer mu.Unlock()
+		enc.Encode(e)
+	})
+}
+
+// params returns the query string of the request followed by the parameters of its body
+func params(r *http.Request, body []byte) url.Values {
+	p := r.URL.Query()
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return p
+	}
+	req := r.Clone(r.Context())
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	values, err := twiml.RequestValues(req)
+	if err != nil {
+		return p
+	}
+	for k, v := range values {
+		p[k] = append(p[k], v...)
+	}
+	return p
+}
+
+// readLog reads every entry of a JSONL log
+func readLog(r io.Reader) ([]entry, error) {
+	var entries []entry
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; s.Scan(); line++ {
+		if len(bytes.TrimSpace(s.Bytes())) == 0 {
+			continue
+		}
+		var e entry
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("log line %d: %s", line, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, s.Err()
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BTBurke/twiml"
+	"github.com/BTBurke/twiml/twimltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func greeter(timeout int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var vr twiml.VoiceRequest
+		twiml.Bind(&vr, r)
+		res := twiml.NewResponse()
+		res.Add(&twiml.Say{Text: "Hello " + vr.From}, &twiml.Dial{Number: "+15550001111", Timeout: timeout})
+		b, _ := res.Encode()
+		w.Write(b)
+	})
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	var log bytes.Buffer
+	h := record(greeter(15), &log)
+	_, err := twimltest.IncomingCall("+15551112222", "+15553334444").To("https://example.com/call?leg=1").Serve(h)
+	assert.NoError(t, err)
+
+	captured := log.Bytes()
+	entries, err := readLog(bytes.NewReader(captured))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "/call?leg=1", entries[0].URL)
+	assert.Equal(t, "+15551112222", entries[0].Call.From)
+	assert.Equal(t, []string{"1"}, entries[0].Params["leg"])
+	assert.Equal(t, 200, entries[0].Status)
+
+	same := httptest.NewServer(greeter(15))
+	defer same.Close()
+	var changed *httptest.Server
+	changed = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := twiml.VerifySignature("secret", changed.URL+r.URL.String(), r); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		greeter(30).ServeHTTP(w, r)
+	}))
+	defer changed.Close()
+
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	logPath := filepath.Join(dir, "twiml.jsonl")
+	assert.NoError(t, ioutil.WriteFile(logPath, captured, 0644))
+
+	status, out := runCmd("", "replay", "-log", logPath, "-target", same.URL)
+	assert.Equal(t, 0, status)
+	assert.Equal(t, "#0 POST /call?leg=1: unchanged\n", out)
+
+	status, out = runCmd("", "replay", "-log", logPath, "-target", changed.URL, "-n", "0", "-auth-token", "secret")
+	assert.Equal(t, 1, status)
+	assert.Equal(t, "#0 POST /call?leg=1: 1 changes\n    Dial[0].timeout: 15 -> 30\n", out)
+}
+
+func TestRecordEmptyResponse(t *testing.T) {
+	empty := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	var log bytes.Buffer
+	rec := twimltest.IncomingCall("+15551112222", "+15553334444").Record(record(empty, &log))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	entries, err := readLog(bytes.NewReader(log.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, entries[0].Status)
+
+	target := httptest.NewServer(empty)
+	defer target.Close()
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	logPath := filepath.Join(dir, "twiml.jsonl")
+	assert.NoError(t, ioutil.WriteFile(logPath, log.Bytes(), 0644))
+
+	status, out := runCmd("", "replay", "-log", logPath, "-target", target.URL)
+	assert.Equal(t, 0, status)
+	assert.Equal(t, "#0 POST /twiml: unchanged\n", out)
+}
+
+func TestRecordJSONParams(t *testing.T) {
+	var log bytes.Buffer
+	h := record(greeter(15), &log)
+	req := httptest.NewRequest(http.MethodPost, "https://example.com/call?leg=2", strings.NewReader(`{"From": "+15551112222", "CallSid": "CA123"}`))
+	req.Header.Set("Content-Type", "application/json")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries, err := readLog(bytes.NewReader(log.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2"}, entries[0].Params["leg"])
+	assert.Equal(t, []string{"+15551112222"}, entries[0].Params["From"])
+	assert.Equal(t, []string{"CA123"}, entries[0].Params["CallSid"])
+	assert.Equal(t, "+15551112222", entries[0].Call.From)
+
+	target := httptest.NewServer(greeter(15))
+	defer target.Close()
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	logPath := filepath.Join(dir, "twiml.jsonl")
+	assert.NoError(t, ioutil.WriteFile(logPath, log.Bytes(), 0644))
+
+	status, out := runCmd("", "replay", "-log", logPath, "-target", target.URL, "-auth-token", "secret")
+	assert.Equal(t, 1, status)
+	assert.Equal(t, "#0 POST /call?leg=2: can not sign a request with content type \"application/json\"\n", out)
+}
+
+func TestDirHandler(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	os.MkdirAll(filepath.Join(dir, "menu"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "index.xml"), []byte("<Response><Hangup/></Response>"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "menu", "sales.xml"), []byte("<Response><Say>Sales</Say></Response>"), 0644)
+	h := dirHandler(dir)
+
+	res, err := twimltest.IncomingCall("+15551112222", "+15553334444").To("https://example.com/").Serve(h)
+	assert.NoError(t, err)
+	assert.Equal(t, []twiml.Markup{&twiml.Hangup{}}, res.Children)
+
+	res, err = twimltest.GatherAction("1").To("https://example.com/menu/sales").Serve(h)
+	assert.NoError(t, err)
+	assert.Equal(t, []twiml.Markup{&twiml.Say{Text: "Sales"}}, res.Children)
+
+	_, err = twimltest.GatherAction("1").To("https://example.com/missing").Serve(h)
+	assert.Error(t, err)
+}
@@ -0,0 +1,329 @@
+package twiml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Decode parses an XML encoded TwiML response.  Verbs are decoded into the same
+// structs used to construct a response.  The response is not validated.
+func Decode(b []byte) (*Response, error) {
+	d := xml.NewDecoder(bytes.NewReader(b))
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			r := NewResponse()
+			if err := r.decode(d, start, &lineIndex{data: b}); err != nil {
+				return nil, err
+			}
+			return r, nil
+		}
+	}
+}
+
+// UnmarshalXML decodes a Response element and all of its nested verbs.  Lines are only
+// reported in errors when decoding with Decode.
+func (r *Response) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return r.decode(d, start, nil)
+}
+
+func (r *Response) decode(d *xml.Decoder, start xml.StartElement, lines *lineIndex) error {
+	if start.Name.Local != "Response" {
+		return fmt.Errorf("Expected Response element, found '%s'", start.Name.Local)
+	}
+	n, err := readNode(d, start, lines)
+	if err != nil {
+		return err
+	}
+	r.Children = nil
+	r.decoded = n
+	return setMarkup(r, n)
+}
+
+// lineIndex converts the input offsets of a decoder to lines of the document.  Offsets must
+// not decrease between calls.
+type lineIndex struct {
+	data []byte
+	off  int64
+	line int
+}
+
+// at returns the line of the offset, or zero when the document is not known
+func (l *lineIndex) at(off int64) int {
+	if l == nil {
+		return 0
+	}
+	if off > int64(len(l.data)) {
+		off = int64(len(l.data))
+	}
+	if off > l.off {
+		l.line += bytes.Count(l.data[l.off:off], []byte("\n"))
+		l.off = off
+	}
+	return l.line + 1
+}
+
+// DecodeError reports markup that can not be decoded into a TwiML verb
+type DecodeError struct {
+	// Line is the line of the element in the document, or 0 when unknown
+	Line int
+	Msg  string
+}
+
+// Error returns the message, prefixed with the line when it is known
+func (e DecodeError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+	}
+	return e.Msg
+}
+
+func decodeError(n *node, format string, args ...interface{}) error {
+	return DecodeError{Line: n.Line, Msg: fmt.Sprintf(format, args...)}
+}
+
+// node is a generic representation of a TwiML element.  It is also the schema of TwiML
+// written as JSON or YAML.
+type node struct {
+	Name     string            `json:"verb" yaml:"verb"`
+	Line     int               `json:"-" yaml:"-"`
+	Attrs    map[string]string `json:"attrs,omitempty" yaml:"attrs,omitempty"`
+	Text     string            `json:"text,omitempty" yaml:"text,omitempty"`
+	Children []*node           `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+// readNode reads the element opened by start into a node, consuming tokens up to
+// and including its end element
+func readNode(d *xml.Decoder, start xml.StartElement, lines *lineIndex) (*node, error) {
+	n := &node{Name: start.Name.Local, Line: lines.at(d.InputOffset()), Attrs: make(map[string]string)}
+	for _, a := range start.Attr {
+		n.Attrs[a.Name.Local] = a.Value
+	}
+	var text strings.Builder
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			child, err := readNode(d, t, lines)
+			if err != nil {
+				return nil, err
+			}
+			n.Children = append(n.Children, child)
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			n.Text = strings.TrimSpace(text.String())
+			return n, nil
+		}
+	}
+}
+
+// newMarkup creates the markup registered for the node's element name
+func newMarkup(n *node) (Markup, error) {
+	newVerb, ok := verbs[n.Name]
+	if !ok {
+		return nil, decodeError(n, "Unknown TwiML verb '%s'", n.Name)
+	}
+	m := newVerb()
+	if err := setMarkup(m, n); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// setMarkup sets the attributes, text and children of the node on the markup struct
+func setMarkup(m Markup, n *node) error {
+	v := reflect.ValueOf(m).Elem()
+	fs := markupFields(v.Type())
+
+	for name, value := range n.Attrs {
+		i, ok := fs.attrs[name]
+		if !ok {
+			return decodeError(n, "Unknown attribute '%s' on %s", name, n.Name)
+		}
+		if err := setField(v.Field(i), value); err != nil {
+			return decodeError(n, "Invalid value for attribute '%s' on %s: %s", name, n.Name, err)
+		}
+	}
+
+	if n.Text != "" {
+		if fs.text < 0 {
+			return decodeError(n, "%s can not contain text", n.Name)
+		}
+		v.Field(fs.text).SetString(n.Text)
+	}
+
+	for _, child := range n.Children {
+		if i, ok := fs.elems[child.Name]; ok {
+			v.Field(i).SetString(child.Text)
+			continue
+		}
+		if fs.children < 0 {
+			return decodeError(child, "%s can not contain %s", n.Name, child.Name)
+		}
+		cm, err := newMarkup(child)
+		if err != nil {
+			return err
+		}
+		children := v.Field(fs.children)
+		children.Set(reflect.Append(children, reflect.ValueOf(cm)))
+	}
+	return nil
+}
+
+// setField converts an attribute value to the kind of the field
+func setField(f reflect.Value, value string) error {
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(value)
+	case reflect.Int:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		f.SetInt(int64(i))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported field type %s", f.Type())
+	}
+	return nil
+}
+
+// toNode converts markup into a generic node.  Zero valued fields are omitted as they are
+// when encoding.
+func toNode(m Markup) *node {
+	v := reflect.Indirect(reflect.ValueOf(m))
+	fs := markupFields(v.Type())
+	n := &node{Name: fs.name, Attrs: make(map[string]string)}
+	for name, i := range fs.attrs {
+		if value, ok := fieldString(v.Field(i)); ok {
+			n.Attrs[name] = value
+		}
+	}
+	if fs.text >= 0 {
+		n.Text = strings.TrimSpace(v.Field(fs.text).String())
+	}
+	for name, i := range fs.elems {
+		if value := v.Field(i).String(); value != "" {
+			n.Children = append(n.Children, &node{Name: name, Text: value})
+		}
+	}
+	if c, ok := m.(Container); ok {
+		for _, child := range c.Kids() {
+			n.Children = append(n.Children, toNode(child))
+		}
+	}
+	return n
+}
+
+// elementName returns the XML element name of the markup, or its type when the name is not
+// declared on an XMLName field
+func elementName(m Markup) string {
+	v := reflect.Indirect(reflect.ValueOf(m))
+	if v.Kind() == reflect.Struct {
+		if name := markupFields(v.Type()).name; name != "" {
+			return name
+		}
+	}
+	return m.Type()
+}
+
+// fieldString formats a field as an attribute value, returning false for zero values
+func fieldString(f reflect.Value) (string, bool) {
+	switch f.Kind() {
+	case reflect.String:
+		return f.String(), f.String() != ""
+	case reflect.Int:
+		return strconv.Itoa(int(f.Int())), f.Int() != 0
+	case reflect.Bool:
+		return strconv.FormatBool(f.Bool()), f.Bool()
+	}
+	return "", false
+}
+
+// fieldSet maps the XML structure of a markup struct to its field indexes
+type fieldSet struct {
+	// name is the XML element name
+	name string
+	// attrs maps attribute names to fields
+	attrs map[string]int
+	// elems maps the names of text-only child elements to fields
+	elems map[string]int
+	// text is the chardata field or -1
+	text int
+	// children is the []Markup field or -1
+	children int
+}
+
+var (
+	markupType = reflect.TypeOf((*Markup)(nil)).Elem()
+	fieldCache sync.Map
+)
+
+// markupFields returns the field set of a markup struct type, parsed from its xml tags
+func markupFields(t reflect.Type) *fieldSet {
+	if fs, ok := fieldCache.Load(t); ok {
+		return fs.(*fieldSet)
+	}
+	fs := &fieldSet{
+		attrs:    make(map[string]int),
+		elems:    make(map[string]int),
+		text:     -1,
+		children: -1,
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Name == "XMLName" {
+			fs.name = strings.Split(f.Tag.Get("xml"), ",")[0]
+			continue
+		}
+		if f.Type.Kind() == reflect.Slice && f.Type.Elem() == markupType {
+			fs.children = i
+			continue
+		}
+		tag := f.Tag.Get("xml")
+		if tag == "-" {
+			continue
+		}
+		opts := strings.Split(tag, ",")
+		name := opts[0]
+		switch {
+		case hasOpt(opts[1:], "attr"):
+			fs.attrs[name] = i
+		case hasOpt(opts[1:], "chardata"):
+			fs.text = i
+		case f.Type.Kind() == reflect.String:
+			if name == "" {
+				name = f.Name
+			}
+			fs.elems[name] = i
+		}
+	}
+	fieldCache.Store(t, fs)
+	return fs
+}
+
+func hasOpt(opts []string, opt string) bool {
+	for _, o := range opts {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package twiml
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Decoding TwiML", func() {
+	It("can decode nested verbs and nouns", func() {
+		b := []byte(buildResponse(
+			x("<Say voice=\"alice\" loop=\"2\">Hello</Say>", 2),
+			x("<Dial timeout=\"15\" hangupOnStar=\"true\">415-999-9999", 2),
+			x("<Client>test</Client>", 4),
+			x("</Dial>", 2),
+			x("<Message to=\"+15555555555\">Sent</Message>", 2),
+		))
+		r, err := Decode(b)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(r.Children).To(Equal([]Markup{
+			&Say{Voice: "alice", Loop: 2, Text: "Hello"},
+			&Dial{Timeout: 15, HangupOnStar: true, Number: "415-999-9999", Children: []Markup{&Client{Name: "test"}}},
+			&Sms{To: "+15555555555", Text: "Sent"},
+		}))
+	})
+
+	It("round trips an encoded response", func() {
+		r := NewResponse()
+		c := &Client{Identity: "alice"}
+		c.Add(&Parameter{Name: "FirstName", Value: "Alice"})
+		d := &Dial{Action: "https://testurl.com"}
+		d.Add(c)
+		g := &Gather{NumDigits: 1}
+		g.Add(&Say{Text: "Press 1"}, &Pause{Length: 2})
+		r.Add(d, g, &Hangup{})
+		b, err := r.Encode()
+		Expect(err).ToNot(HaveOccurred())
+
+		decoded, err := Decode(b)
+		Expect(err).ToNot(HaveOccurred())
+		again, err := decoded.Encode()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(again)).To(Equal(string(b)))
+	})
+
+	It("errors on unknown verbs and attributes", func() {
+		_, err := Decode([]byte(buildResponse(x("<Shout>Hello</Shout>", 2))))
+		Expect(err).To(HaveOccurred())
+		_, err = Decode([]byte(buildResponse(x("<Say volume=\"11\">Hello</Say>", 2))))
+		Expect(err).To(HaveOccurred())
+		_, err = Decode([]byte(buildResponse(x("<Pause length=\"long\"></Pause>", 2))))
+		Expect(err).To(HaveOccurred())
+		_, err = Decode([]byte(buildResponse(x("<Say><Play>test.mp3</Play></Say>", 2))))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("reports the line of markup that can not be decoded", func() {
+		_, err := Decode([]byte(buildResponse(
+			x("<Say>Hello</Say>", 2),
+			x("<Say volume=\"11\">Hello</Say>", 2),
+		)))
+		Expect(err).To(Equal(DecodeError{Line: 4, Msg: "Unknown attribute 'volume' on Say"}))
+	})
+})
@@ -0,0 +1,130 @@
+package twiml
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ChangeKind describes how a verb or attribute differs between two responses
+type ChangeKind int
+
+// Kinds of changes reported by Diff
+const (
+	Added ChangeKind = iota
+	Removed
+	Changed
+)
+
+// Change is a single difference between two responses
+type Change struct {
+	Kind ChangeKind
+	// Path locates the verb, such as Gather[0]/Say[1], followed by .attribute for
+	// attribute changes.  Verbs are indexed among siblings of the same type.
+	Path string
+	// From and To are the attribute or text values before and after a change
+	From string
+	To   string
+}
+
+// String returns a readable representation of the change
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return "+ " + c.Path
+	case Removed:
+		return "- " + c.Path
+	default:
+		return fmt.Sprintf("%s: %s -> %s", c.Path, c.From, c.To)
+	}
+}
+
+// Diff reports the verbs and attributes added, removed or changed from response a to response b.
+// Children of each verb are aligned by type so that inserting a verb reports a single addition.
+func Diff(a *Response, b *Response) []Change {
+	return diffNodes("", toNode(a), toNode(b))
+}
+
+func diffNodes(path string, a *node, b *node) []Change {
+	var changes []Change
+
+	keys := make([]string, 0, len(a.Attrs)+len(b.Attrs))
+	for k := range a.Attrs {
+		keys = append(keys, k)
+	}
+	for k := range b.Attrs {
+		if _, ok := a.Attrs[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if a.Attrs[k] != b.Attrs[k] {
+			changes = append(changes, Change{Kind: Changed, Path: path + "." + k, From: a.Attrs[k], To: b.Attrs[k]})
+		}
+	}
+	if a.Text != b.Text {
+		changes = append(changes, Change{Kind: Changed, Path: path, From: a.Text, To: b.Text})
+	}
+
+	aPaths, bPaths := childPaths(path, a.Children), childPaths(path, b.Children)
+	i, j := 0, 0
+	for _, m := range alignChildren(a.Children, b.Children) {
+		for ; i < m[0]; i++ {
+			changes = append(changes, Change{Kind: Removed, Path: aPaths[i]})
+		}
+		for ; j < m[1]; j++ {
+			changes = append(changes, Change{Kind: Added, Path: bPaths[j]})
+		}
+		changes = append(changes, diffNodes(aPaths[i], a.Children[i], b.Children[j])...)
+		i, j = i+1, j+1
+	}
+	for ; i < len(a.Children); i++ {
+		changes = append(changes, Change{Kind: Removed, Path: aPaths[i]})
+	}
+	for ; j < len(b.Children); j++ {
+		changes = append(changes, Change{Kind: Added, Path: bPaths[j]})
+	}
+	return changes
+}
+
+// childPaths returns the path of each child node
+func childPaths(path string, children []*node) []string {
+	names := make([]string, len(children))
+	for i, c := range children {
+		names[i] = c.Name
+	}
+	return indexedPaths(path, names)
+}
+
+// alignChildren returns the index pairs of the longest common subsequence of child types
+func alignChildren(a []*node, b []*node) [][2]int {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i].Name == b[j].Name:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var pairs [][2]int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i].Name == b[j].Name:
+			pairs = append(pairs, [2]int{i, j})
+			i, j = i+1, j+1
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return pairs
+}
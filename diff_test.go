@@ -0,0 +1,48 @@
+package twiml
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diffing responses", func() {
+	build := func(timeout int, verbs ...Markup) *Response {
+		r := NewResponse()
+		d := &Dial{Timeout: timeout, Number: "415-999-9999"}
+		d.Add(&Client{Name: "test"})
+		r.Add(&Say{Text: "Connecting"}, d)
+		r.Add(verbs...)
+		return r
+	}
+
+	It("reports no changes for equal responses", func() {
+		Expect(Diff(build(15), build(15))).To(BeEmpty())
+	})
+
+	It("reports changed attributes by path", func() {
+		changes := Diff(build(15), build(30))
+		Expect(changes).To(Equal([]Change{{Kind: Changed, Path: "Dial[0].timeout", From: "15", To: "30"}}))
+		Expect(changes[0].String()).To(Equal("Dial[0].timeout: 15 -> 30"))
+	})
+
+	It("reports added and removed verbs", func() {
+		a := build(15, &Say{Text: "Goodbye"}, &Hangup{})
+		b := NewResponse()
+		b.Add(&Pause{Length: 1})
+		b.Add(build(15, &Hangup{}).Children...)
+		Expect(Diff(a, b)).To(Equal([]Change{
+			{Kind: Added, Path: "Pause[0]"},
+			{Kind: Removed, Path: "Say[1]"},
+		}))
+	})
+
+	It("reports changes to nested verbs and text", func() {
+		a, b := build(15), build(15)
+		b.Children[1].(*Dial).Children[0].(*Client).Name = "other"
+		b.Children[0].(*Say).Voice = Alice
+		Expect(Diff(a, b)).To(Equal([]Change{
+			{Kind: Changed, Path: "Say[0].voice", From: "", To: "alice"},
+			{Kind: Changed, Path: "Dial[0]/Client[0]", From: "test", To: "other"},
+		}))
+	})
+})
@@ -0,0 +1,218 @@
+// Package forward forwards calls to a list of phones, rings them at once or one after
+// another, and falls back to voicemail when nobody answers.
+//
+//	vm := voicemail.New("/voicemail", sink)
+//	f := forward.New("/forward", forward.Number("+15550001111"), forward.Client("alice"))
+//	f.Sequential = true
+//	f.Voicemail = vm
+//	http.Handle("/forward/", f)
+//	http.Handle("/voicemail/", vm)
+package forward
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/BTBurke/twiml"
+	"github.com/BTBurke/twiml/voicemail"
+)
+
+// DefaultTimeout is the number of seconds each target rings
+const DefaultTimeout = 20
+
+// Target is a phone number, Twilio Client identity or SIP address to ring.  Exactly one
+// field is set.
+type Target struct {
+	Number string
+	Client string
+	Sip    string
+}
+
+// Number returns a target that dials a phone number
+func Number(number string) Target {
+	return Target{Number: number}
+}
+
+// Client returns a target that rings a Twilio Client identity
+func Client(identity string) Target {
+	return Target{Client: identity}
+}
+
+// Sip returns a target that dials a SIP address
+func Sip(address string) Target {
+	return Target{Sip: address}
+}
+
+// noun returns the Dial noun of the target, with the whisper URL requested when it answers
+func (t Target) noun(whisper string) (twiml.Markup, error) {
+	switch {
+	case t.Number != "" && t.Client == "" && t.Sip == "":
+		return &twiml.Number{Number: t.Number, URL: whisper}, nil
+	case t.Client != "" && t.Number == "" && t.Sip == "":
+		return &twiml.Client{Identity: t.Client, URL: whisper}, nil
+	case t.Sip != "" && t.Number == "" && t.Client == "":
+		return &twiml.Sip{Address: t.Sip, URL: whisper}, nil
+	default:
+		return nil, fmt.Errorf("Target must set exactly one of Number, Client or Sip: %+v", t)
+	}
+}
+
+// Forward serves the TwiML that forwards calls to its targets under a path
+type Forward struct {
+	// Targets are rung in order when Sequential, otherwise all at once
+	Targets []Target
+	// Sequential rings each target in turn until one answers
+	Sequential bool
+	// Timeout is the number of seconds each target rings, or all targets together when
+	// they ring at once
+	Timeout int
+	// CallerID is shown to the targets instead of the number of the caller
+	CallerID string
+	// Whisper is the URL of TwiML played to the target before the call is connected.  The
+	// call is not connected when that TwiML hangs up.
+	Whisper string
+	// Screen asks the target to press a key to accept the call.  It is ignored when
+	// Whisper is set.
+	Screen bool
+	// Voicemail records a message when no target answers.  The call is hung up when it is
+	// nil.
+	Voicemail *voicemail.Voicemail
+
+	path string
+}
+
+// New returns a forward served under path that rings the targets at once for
+// DefaultTimeout seconds
+func New(path string, targets ...Target) *Forward {
+	return &Forward{
+		Targets: targets,
+		Timeout: DefaultTimeout,
+		path:    strings.TrimSuffix(path, "/"),
+	}
+}
+
+// URL returns the path that forwards a call, for use in a Redirect
+func (f *Forward) URL() string {
+	return f.path
+}
+
+// whisper returns the URL requested when a target answers
+func (f *Forward) whisper() string {
+	switch {
+	case f.Whisper != "":
+		return f.Whisper
+	case f.Screen:
+		return f.path + "/screen"
+	default:
+		return ""
+	}
+}
+
+// Render adds the Dial of the attempt to a response.  Attempts count from zero and each one
+// rings the next target when Sequential.  The voicemail fallback is added once the targets
+// are used up.
+func (f *Forward) Render(res *twiml.Response, attempt int) error {
+	targets := f.Targets
+	if f.Sequential {
+		if attempt < len(targets) {
+			targets = targets[attempt : attempt+1]
+		} else {
+			targets = nil
+		}
+	} else if attempt > 0 {
+		targets = nil
+	}
+	if len(targets) == 0 {
+		f.fallback(res)
+		return nil
+	}
+
+	d := &twiml.Dial{
+		Action:   f.path + "/status?attempt=" + strconv.Itoa(attempt+1),
+		Method:   http.MethodPost,
+		Timeout:  f.Timeout,
+		CallerID: f.CallerID,
+	}
+	for _, t := range targets {
+		n, err := t.noun(f.whisper())
+		if err != nil {
+			return err
+		}
+		d.Add(n)
+	}
+	res.Add(d)
+	return nil
+}
+
+func (f *Forward) fallback(res *twiml.Response) {
+	if f.Voicemail != nil {
+		f.Voicemail.Render(res)
+		return
+	}
+	res.Add(&twiml.Hangup{})
+}
+
+// ServeHTTP forwards calls at path, continues with the next target at path/status when a
+// Dial ends without being bridged, and screens answered calls at path/screen
+func (f *Forward) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != f.path && !strings.HasPrefix(r.URL.Path, f.path+"/") {
+		http.NotFound(w, r)
+		return
+	}
+	rest := r.URL.Path[len(f.path):]
+
+	res := twiml.NewResponse()
+	var err error
+	switch strings.Trim(rest, "/") {
+	case "":
+		err = f.Render(res, 0)
+	case "status":
+		var dr twiml.DialActionRequest
+		if err := twiml.Bind(&dr, r); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch {
+		case dr.DialBridged:
+			res.Add(&twiml.Hangup{})
+		case dr.DialCallStatus == twiml.Canceled:
+			// the caller hung up while the targets were ringing
+			res.Add(&twiml.Hangup{})
+		default:
+			// a completed Dial that was not bridged was declined at the screen or whisper
+			err = f.Render(res, attempt(r.URL.Query()))
+		}
+	case "screen":
+		g := &twiml.Gather{Action: f.path + "/screen/accept", Method: http.MethodPost, NumDigits: 1}
+		g.Add(&twiml.Say{Text: "You have an incoming call.  Press any key to accept."})
+		res.Add(g, &twiml.Hangup{})
+	case "screen/accept":
+		res.Add(&twiml.Say{Text: "Connecting."})
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	b, err := res.Encode()
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.Write(b)
+}
+
+// attempt returns the number of Dials that ended unanswered
+func attempt(q url.Values) int {
+	n, err := strconv.Atoi(q.Get("attempt"))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,174 @@
+package forward
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/BTBurke/twiml"
+	"github.com/BTBurke/twiml/twimltest"
+	"github.com/BTBurke/twiml/voicemail"
+	"github.com/stretchr/testify/assert"
+)
+
+type sink struct {
+	recorded []*voicemail.Message
+}
+
+func (s *sink) Recorded(ctx context.Context, m *voicemail.Message) error {
+	s.recorded = append(s.recorded, m)
+	return nil
+}
+
+func (s *sink) RecordingStatus(ctx context.Context, m *voicemail.Message) error { return nil }
+
+func (s *sink) Transcribed(ctx context.Context, m *voicemail.Message) error { return nil }
+
+func targets() []Target {
+	return []Target{Number("+15550001111"), Client("alice"), Sip("sip:bob@example.com")}
+}
+
+func simulate(t *testing.T, f *Forward, outcomes ...string) *twimltest.Result {
+	mux := http.NewServeMux()
+	mux.Handle("/forward/", f)
+	mux.Handle("/forward", f)
+	if f.Voicemail != nil {
+		mux.Handle("/voicemail/", f.Voicemail)
+		mux.Handle("/voicemail", f.Voicemail)
+	}
+	s := &twimltest.Simulator{
+		Handler: mux,
+		URL:     "https://example.com/forward",
+		Script:  twimltest.Script{DialOutcomes: outcomes, RecordingDuration: 4},
+	}
+	res, err := s.Run()
+	assert.NoError(t, err)
+	return res
+}
+
+func TestSimultaneous(t *testing.T) {
+	res := simulate(t, New("/forward", targets()...), twiml.Completed)
+	assert.Equal(t, []string{
+		"Dial: +15550001111, client:alice, sip:bob@example.com (completed)",
+		"Hangup",
+	}, res.Lines())
+}
+
+func TestSimultaneousVoicemail(t *testing.T) {
+	s := &sink{}
+	f := New("/forward", targets()...)
+	f.Voicemail = voicemail.New("/voicemail", s)
+	res := simulate(t, f, twiml.NoAnswer)
+	assert.Equal(t, []string{
+		"Dial: +15550001111, client:alice, sip:bob@example.com (no-answer)",
+		"Say: Please leave a message after the beep.",
+		"Record: 4s",
+		"Hangup",
+	}, res.Lines())
+	assert.Len(t, s.recorded, 1)
+}
+
+func TestSequential(t *testing.T) {
+	f := New("/forward", targets()...)
+	f.Sequential = true
+	res := simulate(t, f, twiml.Busy, twiml.NoAnswer, twiml.Completed)
+	assert.Equal(t, []string{
+		"Dial: +15550001111 (busy)",
+		"Dial: client:alice (no-answer)",
+		"Dial: sip:bob@example.com (completed)",
+		"Hangup",
+	}, res.Lines())
+	assert.Equal(t, "https://example.com/forward/status?attempt=2", res.Requests[2].URL)
+}
+
+func TestSequentialAnswered(t *testing.T) {
+	f := New("/forward", targets()...)
+	f.Sequential = true
+	f.Voicemail = voicemail.New("/voicemail", &sink{})
+	res := simulate(t, f, twiml.NoAnswer, twiml.Completed)
+	assert.Equal(t, []string{
+		"Dial: +15550001111 (no-answer)",
+		"Dial: client:alice (completed)",
+		"Hangup",
+	}, res.Lines())
+}
+
+func TestSequentialDeclined(t *testing.T) {
+	f := New("/forward", targets()...)
+	f.Sequential = true
+	f.Screen = true
+	res, err := twimltest.DialAction(twiml.Completed).Set("DialBridged", "false").
+		To("https://example.com/forward/status?attempt=1").Serve(f)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", res.Children[0].(*twiml.Dial).Children[0].(*twiml.Client).Identity)
+
+	f.Sequential = false
+	f.Voicemail = voicemail.New("/voicemail", &sink{})
+	res, err = twimltest.DialAction(twiml.Completed).Set("DialBridged", "false").
+		To("https://example.com/forward/status?attempt=1").Serve(f)
+	assert.NoError(t, err)
+	assert.IsType(t, &twiml.Say{}, res.Children[0])
+}
+
+func TestSequentialHangup(t *testing.T) {
+	f := New("/forward", targets()...)
+	f.Sequential = true
+	res := simulate(t, f, twiml.Failed, twiml.Busy, twiml.NoAnswer)
+	assert.Equal(t, "Hangup", res.EndedBy)
+	assert.Len(t, res.Requests, 4)
+
+	res = simulate(t, f, twiml.Busy, twiml.Canceled)
+	assert.Equal(t, []string{
+		"Dial: +15550001111 (busy)",
+		"Dial: client:alice (canceled)",
+		"Hangup",
+	}, res.Lines())
+}
+
+func TestWhisper(t *testing.T) {
+	f := New("/forward", targets()...)
+	f.Screen = true
+	res := twiml.NewResponse()
+	assert.NoError(t, f.Render(res, 0))
+	assert.NoError(t, res.Validate())
+	d := res.Children[0].(*twiml.Dial)
+	assert.Equal(t, "/forward/screen", d.Children[0].(*twiml.Number).URL)
+	assert.Equal(t, "/forward/screen", d.Children[1].(*twiml.Client).URL)
+	assert.Equal(t, "/forward/screen", d.Children[2].(*twiml.Sip).URL)
+
+	f.Whisper = "https://example.com/whisper"
+	res = twiml.NewResponse()
+	assert.NoError(t, f.Render(res, 0))
+	assert.Equal(t, f.Whisper, res.Children[0].(*twiml.Dial).Children[0].(*twiml.Number).URL)
+}
+
+func TestScreen(t *testing.T) {
+	f := New("/forward", targets()...)
+	f.Screen = true
+	s := &twimltest.Simulator{
+		Handler: f,
+		URL:     "https://example.com/forward/screen",
+		Script:  twimltest.Script{Inputs: []twimltest.Input{twimltest.Digits("5")}},
+	}
+	res, err := s.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"Say: You have an incoming call.  Press any key to accept.",
+		"Gather: 5",
+		"Say: Connecting.",
+	}, res.Lines())
+
+	s.Script.Inputs = nil
+	res, err = s.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, "Hangup", res.EndedBy)
+}
+
+func TestInvalidTarget(t *testing.T) {
+	f := New("/forward", Target{Number: "+15550001111", Client: "alice"})
+	rec := twimltest.IncomingCall("+15551112222", "+15553334444").To("https://example.com/forward").Record(f)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	rec = twimltest.IncomingCall("+15551112222", "+15553334444").To("https://example.com/forward/other").Record(f)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,544 @@
+// Command twimlgen generates the TwiML verb structs, their validation, decoder registrations,
+// encoders and tests from the schema in vocabulary.yaml.  It is run by go generate in the
+// root of the twiml package:
+//
+//	//go:generate go run ./internal/twimlgen
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Schema is the list of verbs read from the schema file
+type Schema struct {
+	Verbs []*Verb `yaml:"verbs"`
+}
+
+// Verb describes a TwiML verb or noun
+type Verb struct {
+	Element  string     `yaml:"element"`
+	Type     string     `yaml:"type"`
+	Doc      string     `yaml:"doc"`
+	Top      bool       `yaml:"top"`
+	Value    bool       `yaml:"value"`
+	Text     *Text      `yaml:"text"`
+	Elements []*Element `yaml:"elements"`
+	Attrs    []*Attr    `yaml:"attrs"`
+	Children []string   `yaml:"children"`
+	Checks   []string   `yaml:"checks"`
+}
+
+// Text is the field holding the character data of a verb
+type Text struct {
+	Field    string `yaml:"field"`
+	Type     string `yaml:"type"`
+	Required bool   `yaml:"required"`
+}
+
+// Element is a nested element holding only text
+type Element struct {
+	Name  string `yaml:"name"`
+	Field string `yaml:"field"`
+	Doc   string `yaml:"doc"`
+}
+
+// Attr is an attribute of a verb
+type Attr struct {
+	Name     string   `yaml:"name"`
+	Field    string   `yaml:"field"`
+	Type     string   `yaml:"type"`
+	Enum     []string `yaml:"enum"`
+	Required bool     `yaml:"required"`
+	Events   string   `yaml:"events"`
+}
+
+func main() {
+	schemaFile := flag.String("schema", "vocabulary.yaml", "schema `file` describing the verbs")
+	dir := flag.String("dir", ".", "`directory` to write the generated files to")
+	flag.Parse()
+
+	if err := run(*schemaFile, *dir); err != nil {
+		fmt.Fprintf(os.Stderr, "twimlgen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(schemaFile, dir string) error {
+	b, err := ioutil.ReadFile(schemaFile)
+	if err != nil {
+		return err
+	}
+	var s Schema
+	if err := yaml.UnmarshalStrict(b, &s); err != nil {
+		return fmt.Errorf("%s: %s", schemaFile, err)
+	}
+	if err := s.check(); err != nil {
+		return fmt.Errorf("%s: %s", schemaFile, err)
+	}
+
+	for name, tmpl := range map[string]*template.Template{
+		"vocabulary.go":          vocabularyTmpl,
+		"vocabulary_codec.go":    codecTmpl,
+		"vocabulary_gen_test.go": testTmpl,
+	} {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, map[string]interface{}{
+			"Schema": filepath.Base(schemaFile),
+			"Verbs":  s.Verbs,
+		}); err != nil {
+			return err
+		}
+		src, err := format.Source(buf.Bytes())
+		if err != nil {
+			return fmt.Errorf("%s: %s", name, err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, name), src, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check fills in defaults and reports schema errors
+func (s *Schema) check() error {
+	types := make(map[string]bool)
+	for _, v := range s.Verbs {
+		if v.Element == "" {
+			return fmt.Errorf("verb without an element name")
+		}
+		if v.Type == "" {
+			v.Type = v.Element
+		}
+		types[v.Type] = true
+		if t := v.Text; t != nil {
+			switch t.Type {
+			case "":
+				t.Type = "string"
+			case "string", "url":
+			default:
+				return fmt.Errorf("%s: unknown text type '%s'", v.Element, t.Type)
+			}
+		}
+		for _, e := range v.Elements {
+			if e.Field == "" {
+				e.Field = fieldName(e.Name)
+			}
+		}
+		for _, a := range v.Attrs {
+			if a.Field == "" {
+				a.Field = fieldName(a.Name)
+			}
+			switch a.Type {
+			case "":
+				a.Type = "string"
+			case "string", "int", "bool", "method", "digits", "dtmf", "url":
+			case "events":
+				if a.Events == "" {
+					return fmt.Errorf("%s.%s: events attribute without a validator", v.Element, a.Name)
+				}
+			default:
+				return fmt.Errorf("%s.%s: unknown type '%s'", v.Element, a.Name, a.Type)
+			}
+			if len(a.Enum) > 0 && a.Type != "string" {
+				return fmt.Errorf("%s.%s: enum must be a string", v.Element, a.Name)
+			}
+		}
+	}
+	for _, v := range s.Verbs {
+		for _, c := range v.Children {
+			if !types[c] {
+				return fmt.Errorf("%s: unknown child '%s'", v.Element, c)
+			}
+		}
+	}
+	return nil
+}
+
+// Recv is the receiver name used in methods of the verb
+func (v *Verb) Recv() string {
+	return strings.ToLower(v.Type[:1])
+}
+
+// Ptr is the receiver type used in methods of the verb
+func (v *Verb) Ptr() string {
+	if v.Value {
+		return v.Type
+	}
+	return "*" + v.Type
+}
+
+// Validators are the Go expressions that must all be true for the verb to be valid
+func (v *Verb) Validators() []string {
+	var out []string
+	for _, a := range v.Attrs {
+		f := v.Recv() + "." + a.Field
+		switch a.Type {
+		case "method":
+			out = append(out, fmt.Sprintf("AllowedMethod(%s)", f))
+		case "digits":
+			out = append(out, fmt.Sprintf("NumericOpt(%s)", f))
+		case "dtmf":
+			out = append(out, fmt.Sprintf("NumericOrWait(%s)", f))
+		case "url":
+			out = append(out, fmt.Sprintf("AllowedURL(%s)", f))
+		case "events":
+			out = append(out, fmt.Sprintf("AllowedCallbackEvent(%s, %s)", f, a.Events))
+		}
+		if len(a.Enum) > 0 {
+			opts := []string{f}
+			for _, e := range a.Enum {
+				opts = append(opts, strconv.Quote(e))
+			}
+			out = append(out, fmt.Sprintf("OneOfOpt(%s)", strings.Join(opts, ", ")))
+		}
+		if a.Required {
+			out = append(out, fmt.Sprintf("Required(%s)", f))
+		}
+	}
+	if v.Text != nil && v.Text.Type == "url" {
+		out = append(out, fmt.Sprintf("AllowedURL(%s.%s)", v.Recv(), v.Text.Field))
+	}
+	if v.Text != nil && v.Text.Required {
+		out = append(out, fmt.Sprintf("Required(%s.%s)", v.Recv(), v.Text.Field))
+	}
+	return append(out, v.Checks...)
+}
+
+// URLs are the fields of the verb holding webhook URLs, which may be relative
+func (v *Verb) URLs() []string {
+	var out []string
+	if v.Text != nil && v.Text.Type == "url" {
+		out = append(out, v.Text.Field)
+	}
+	for _, a := range v.Attrs {
+		if a.Type == "url" {
+			out = append(out, a.Field)
+		}
+	}
+	return out
+}
+
+// GoType is the type of the struct field holding the attribute
+func (a *Attr) GoType() string {
+	switch a.Type {
+	case "int", "bool":
+		return a.Type
+	}
+	return "string"
+}
+
+// Validated reports whether some values of the attribute are rejected by Validate
+func (a *Attr) Validated() bool {
+	switch a.Type {
+	case "method", "digits", "dtmf", "events", "url":
+		return true
+	}
+	return len(a.Enum) > 0
+}
+
+// Invalid is the Go literal of a value of the attribute rejected by Validate
+func (a *Attr) Invalid() string {
+	if a.Type == "url" {
+		return strconv.Quote("ftp://example.com/" + a.Name)
+	}
+	return strconv.Quote("invalid value")
+}
+
+// Sample is a valid value for the attribute as written in XML, used in generated tests
+func (a *Attr) Sample(i int) string {
+	switch {
+	case len(a.Enum) > 0:
+		return a.Enum[0]
+	case a.Type == "int":
+		return strconv.Itoa(i + 1)
+	case a.Type == "bool":
+		return "true"
+	case a.Type == "method":
+		return "POST"
+	case a.Type == "digits":
+		return "1234"
+	case a.Type == "dtmf":
+		return "ww1"
+	}
+	return a.Name
+}
+
+// Literal is the Go literal of the sample value for the attribute
+func (a *Attr) Literal(i int) string {
+	if a.GoType() == "string" {
+		return strconv.Quote(a.Sample(i))
+	}
+	return a.Sample(i)
+}
+
+// fieldName exports an attribute name, capitalizing initialisms the way golint expects
+func fieldName(name string) string {
+	var words []string
+	start := 0
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			words = append(words, name[start:i])
+			start = i
+		}
+	}
+	words = append(words, name[start:])
+	for i, w := range words {
+		switch strings.ToLower(w) {
+		case "url", "id":
+			words[i] = strings.ToUpper(w)
+		default:
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, "")
+}
+
+var funcs = template.FuncMap{
+	"quote": strconv.Quote,
+	"join":  strings.Join,
+	"comment": func(s string) string {
+		return "// " + strings.Replace(s, "\n", "\n// ", -1)
+	},
+	"quoteAll": func(ss []string) string {
+		var q []string
+		for _, s := range ss {
+			q = append(q, strconv.Quote(s))
+		}
+		return strings.Join(q, ", ")
+	},
+}
+
+var vocabularyTmpl = template.Must(template.New("vocabulary").Funcs(funcs).Parse(`// Code generated by twimlgen from {{.Schema}}. DO NOT EDIT.
+
+package twiml
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// verbs maps TwiML element names to a constructor for the markup they decode into
+var verbs = map[string]func() Markup{
+{{- range .Verbs}}
+	{{quote .Element}}: func() Markup { return &{{.Type}}{} },
+{{- end}}
+}
+
+// responseVerbs are the types of the verbs allowed directly in a Response
+var responseVerbs = map[string]bool{
+{{- range .Verbs}}{{if .Top}}
+	{{quote .Type}}: true,
+{{- end}}{{end}}
+}
+{{range .Verbs}}{{$v := .}}
+{{comment .Doc}}
+type {{.Type}} struct {
+	XMLName xml.Name ` + "`" + `xml:"{{.Element}}"` + "`" + `
+{{- with .Text}}
+	{{.Field}} string ` + "`" + `xml:",chardata"` + "`" + `
+{{- end}}
+{{- range .Elements}}
+	{{.Field}} string ` + "`" + `xml:"{{.Name}},omitempty"` + "`" + `{{with .Doc}} // {{.}}{{end}}
+{{- end}}
+{{- if or .Text .Elements}}{{if .Attrs}}
+{{end}}{{end}}
+{{- range .Attrs}}
+	{{.Field}} {{.GoType}} ` + "`" + `xml:"{{.Name}},attr,omitempty"` + "`" + `
+{{- end}}
+{{- if .Children}}
+	Children []Markup ` + "`" + `xml:",omitempty"` + "`" + `
+{{- end}}
+}
+{{if .Children}}
+// Add nests markup in the {{.Type}}.  Valid children: {{join .Children ", "}}
+func ({{.Recv}} {{.Ptr}}) Add(ml ...Markup) {
+	{{.Recv}}.Children = append({{.Recv}}.Children, ml...)
+}
+
+// Kids returns the markup nested in the {{.Type}}
+func ({{.Recv}} {{.Ptr}}) Kids() []Markup {
+	return {{.Recv}}.Children
+}
+
+// SetKids replaces the markup nested in the {{.Type}}
+func ({{.Recv}} {{.Ptr}}) SetKids(ml []Markup) {
+	{{.Recv}}.Children = ml
+}
+{{end}}
+// Validate returns an error if the TwiML is constructed improperly
+func ({{.Recv}} {{.Ptr}}) Validate() error {
+{{- if .Children}}
+	var errs []error
+	for _, m := range {{.Recv}}.Children {
+		switch m.Type() {
+		case {{quoteAll .Children}}:
+			if err := m.Validate(); err != nil {
+				errs = append(errs, err)
+			}
+		default:
+			return fmt.Errorf("Not a valid verb as child of %s: '%T'", {{.Recv}}.Type(), m)
+		}
+	}
+{{- with .Validators}}
+	ok := Validate(
+{{- range .}}
+		{{.}},
+{{- end}}
+	)
+	if !ok {
+		errs = append(errs, fmt.Errorf("%s markup failed validation", {{$v.Recv}}.Type()))
+	}
+{{- end}}
+	if len(errs) > 0 {
+		return ValidationError{errs}
+	}
+	return nil
+{{- else if .Validators}}
+	ok := Validate(
+{{- range .Validators}}
+		{{.}},
+{{- end}}
+	)
+	if !ok {
+		return fmt.Errorf("%s markup failed validation", {{.Recv}}.Type())
+	}
+	return nil
+{{- else}}
+	return nil
+{{- end}}
+}
+
+// Type returns the name of the verb
+func ({{.Recv}} {{.Ptr}}) Type() string {
+	return {{quote .Type}}
+}
+{{end}}`))
+
+var codecTmpl = template.Must(template.New("codec").Funcs(funcs).Parse(`// Code generated by twimlgen from {{.Schema}}. DO NOT EDIT.
+
+package twiml
+{{range .Verbs}}
+func ({{.Recv}} {{.Ptr}}) writeTwiML(w *twimlWriter) {
+	w.start({{quote .Element}})
+{{- $r := .Recv}}
+{{- range .Attrs}}
+	w.{{if eq .GoType "int"}}attrInt{{else if eq .GoType "bool"}}attrBool{{else}}attr{{end}}({{quote .Name}}, {{$r}}.{{.Field}})
+{{- end}}
+	w.closeStart()
+{{- with .Text}}
+	w.text({{$r}}.{{.Field}})
+{{- end}}
+{{- range .Elements}}
+	w.textElement({{quote .Name}}, {{$r}}.{{.Field}})
+{{- end}}
+{{- if .Children}}
+	w.children({{.Recv}}.Children)
+{{- end}}
+	w.end({{quote .Element}})
+}
+{{- if .URLs}}
+
+// urls returns the fields of the verb holding webhook URLs
+func ({{.Recv}} {{.Ptr}}) urls() []*string {
+	return []*string{ {{- range $i, $f := .URLs}}{{if $i}}, {{end}}&{{$r}}.{{$f}}{{end -}} }
+}
+{{- end}}
+
+// MarshalJSON encodes the verb as JSON
+func ({{.Recv}} {{.Type}}) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&{{.Recv}})
+}
+
+// UnmarshalJSON decodes the verb from JSON
+func ({{.Recv}} *{{.Type}}) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON({{.Recv}}, b)
+}
+
+// MarshalYAML encodes the verb as YAML
+func ({{.Recv}} {{.Type}}) MarshalYAML() (interface{}, error) {
+	return marshalYAML(&{{.Recv}})
+}
+
+// UnmarshalYAML decodes the verb from YAML
+func ({{.Recv}} *{{.Type}}) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML({{.Recv}}, unmarshal)
+}
+{{end}}`))
+
+var testTmpl = template.Must(template.New("test").Funcs(funcs).Parse(`// Code generated by twimlgen from {{.Schema}}. DO NOT EDIT.
+
+package twiml
+
+import (
+	"bytes"
+	"encoding/xml"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generated vocabulary", func() {
+{{- range .Verbs}}{{$v := .}}
+	Context({{quote .Element}}, func() {
+		sample := func() *{{.Type}} {
+			return &{{.Type}}{
+{{- with .Text}}
+				{{.Field}}: "text",
+{{- end}}
+{{- range .Elements}}
+				{{.Field}}: {{quote .Name}},
+{{- end}}
+{{- range $i, $a := .Attrs}}
+				{{.Field}}: {{.Literal $i}},
+{{- end}}
+			}
+		}
+		xmlSample := ` + "`" + `<{{.Element}}{{range $i, $a := .Attrs}} {{.Name}}="{{.Sample $i}}"{{end}}>{{with .Text}}text{{end}}{{range .Elements}}<{{.Name}}>{{.Name}}</{{.Name}}>{{end}}</{{.Element}}>` + "`" + `
+
+		It("decodes every attribute", func() {
+			r, err := Decode([]byte("<Response>" + xmlSample + "</Response>"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Children).To(HaveLen(1))
+			Expect(r.Children[0]).To(Equal(sample()))
+		})
+
+		It("writes the same XML with and without reflection", func() {
+			b, err := xml.Marshal(sample())
+			Expect(err).ToNot(HaveOccurred())
+			var buf bytes.Buffer
+			sample().writeTwiML(&twimlWriter{buf: &buf})
+			Expect(buf.String()).To(Equal(string(b)))
+			Expect(buf.String()).To(Equal(xmlSample))
+		})
+{{- range .Attrs}}{{if .Validated}}
+
+		It("rejects an invalid {{.Name}}", func() {
+			m := sample()
+			m.{{.Field}} = {{.Invalid}}
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+{{- end}}{{end}}
+{{- with .Text}}{{if eq .Type "url"}}
+
+		It("rejects an invalid URL", func() {
+			m := sample()
+			m.{{.Field}} = "ftp://example.com/text"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+{{- end}}{{end}}
+	})
+{{- end}}
+})
+`))
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldName(t *testing.T) {
+	for name, field := range map[string]string{
+		"method":           "Method",
+		"url":              "URL",
+		"callerId":         "CallerID",
+		"waitUrlMethod":    "WaitURLMethod",
+		"eventCallbackUrl": "EventCallbackURL",
+		"reservationSid":   "ReservationSid",
+		"Identity":         "Identity",
+	} {
+		assert.Equal(t, field, fieldName(name), name)
+	}
+}
+
+// TestGeneratedFilesUpToDate fails when the schema was edited without running go generate
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "twimlgen")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, run("../../vocabulary.yaml", dir))
+	for _, name := range []string{"vocabulary.go", "vocabulary_codec.go", "vocabulary_gen_test.go"} {
+		want, err := ioutil.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		got, err := ioutil.ReadFile(filepath.Join("../..", name))
+		require.NoError(t, err)
+		assert.Equal(t, string(want), string(got), "%s is out of date, run go generate", name)
+	}
+}
+
+func TestSchemaErrors(t *testing.T) {
+	for _, tc := range []struct {
+		schema string
+		err    string
+	}{
+		{"verbs: [{type: Say}]", "verb without an element name"},
+		{"verbs: [{element: Say, attrs: [{name: voice, type: voice}]}]", "Say.voice: unknown type 'voice'"},
+		{"verbs: [{element: Say, attrs: [{name: loop, type: int, enum: [a]}]}]", "Say.loop: enum must be a string"},
+		{"verbs: [{element: Sip, attrs: [{name: events, type: events}]}]", "Sip.events: events attribute without a validator"},
+		{"verbs: [{element: Dial, children: [Number]}]", "Dial: unknown child 'Number'"},
+	} {
+		dir, err := ioutil.TempDir("", "twimlgen")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		schema := filepath.Join(dir, "schema.yaml")
+		require.NoError(t, ioutil.WriteFile(schema, []byte(tc.schema), 0644))
+		err = run(schema, dir)
+		if assert.Error(t, err, tc.schema) {
+			assert.Contains(t, err.Error(), tc.err)
+		}
+	}
+}
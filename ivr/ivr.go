@@ -0,0 +1,304 @@
+// Package ivr builds interactive voice response menus as a state machine on top of the
+// Gather, Say and Redirect verbs.  Each menu is a state with a prompt and a map of digits
+// to the next state.  A Machine generates the TwiML for each state, serves the action URL
+// of each Gather, binds the digits from the callback and advances to the next state.
+//
+//	m := ivr.New("/ivr", "main").
+//		Menu(&ivr.Menu{
+//			Name:    "main",
+//			Prompt:  []twiml.Markup{&twiml.Say{Text: "Press 1 for sales or 2 for support"}},
+//			Choices: map[string]string{"1": "sales", "2": "support"},
+//			Retries: 2,
+//		}).
+//		Action("sales", transferToSales).
+//		Action("support", transferToSupport)
+//	http.Handle("/ivr/", m)
+package ivr
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BTBurke/twiml"
+)
+
+// Menu is a state that prompts the caller to choose the next state with the keypad
+type Menu struct {
+	// Name identifies the state and is the last element of its URL
+	Name string
+	// Prompt is nested in the Gather and may contain Say, Play and Pause verbs
+	Prompt []twiml.Markup
+	// Choices maps the digits entered by the caller to the name of the next state
+	Choices map[string]string
+	// NumDigits is the number of digits to gather.  It defaults to the length of the longest
+	// choice, so single digit menus advance as soon as a key is pressed.
+	NumDigits int
+	// Timeout is the number of seconds to wait for input, or the Twilio default when zero
+	Timeout int
+	// Retries is the number of times the prompt is repeated after no input or an invalid
+	// choice before moving to Fallback
+	Retries int
+	// NoInput is played before the prompt is repeated when the caller entered nothing
+	NoInput []twiml.Markup
+	// Invalid is played before the prompt is repeated when the digits are not a choice
+	Invalid []twiml.Markup
+	// Fallback is the state entered when the retries are used up.  The call is hung up when
+	// it is empty.
+	Fallback string
+}
+
+// Action is a state that leaves the menus, such as transferring or hanging up the call.  The
+// digits that chose the action are in call.Digits.  Returning a nil response is an error.
+type Action func(r *http.Request, call *twiml.GatherActionRequest) (*twiml.Response, error)
+
+// Machine serves the states of an IVR under a path
+type Machine struct {
+	path    string
+	start   string
+	menus   map[string]*Menu
+	actions map[string]Action
+}
+
+// New returns a machine served under path that starts in the start state.  Callers enter
+// the machine at the URL of path, or the URL of any state.
+func New(path string, start string) *Machine {
+	return &Machine{
+		path:    strings.TrimSuffix(path, "/"),
+		start:   start,
+		menus:   make(map[string]*Menu),
+		actions: make(map[string]Action),
+	}
+}
+
+// Menu adds a menu state
+func (m *Machine) Menu(menu *Menu) *Machine {
+	m.menus[menu.Name] = menu
+	return m
+}
+
+// Action adds a state that leaves the menus
+func (m *Machine) Action(name string, a Action) *Machine {
+	m.actions[name] = a
+	return m
+}
+
+// URL returns the path at which callers enter the state, for use in a Redirect from
+// outside the machine
+func (m *Machine) URL(state string) string {
+	return m.path + "/" + state
+}
+
+// inputURL returns the action URL of the Gather in a menu
+func (m *Machine) inputURL(state string, attempt int) string {
+	u := m.URL(state) + "/input"
+	if attempt > 0 {
+		u += "?attempt=" + strconv.Itoa(attempt)
+	}
+	return u
+}
+
+// Validate returns an error when a state is defined twice or a transition leads to a state
+// that does not exist
+func (m *Machine) Validate() error {
+	var errs []error
+	exists := func(from string, to string) {
+		if !m.has(to) {
+			errs = append(errs, fmt.Errorf("%s: unknown state '%s'", from, to))
+		}
+	}
+	exists("start", m.start)
+	for name := range m.actions {
+		if _, ok := m.menus[name]; ok {
+			errs = append(errs, fmt.Errorf("%s: defined as both a menu and an action", name))
+		}
+	}
+	for _, name := range m.states() {
+		menu, ok := m.menus[name]
+		if !ok {
+			continue
+		}
+		if len(menu.Choices) == 0 {
+			errs = append(errs, fmt.Errorf("%s: menu without choices", name))
+		}
+		for _, digits := range sortedKeys(menu.Choices) {
+			exists(name, menu.Choices[digits])
+		}
+		if menu.Fallback != "" {
+			exists(name, menu.Fallback)
+		}
+	}
+	if len(errs) > 0 {
+		return twiml.ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+func (m *Machine) has(state string) bool {
+	_, menu := m.menus[state]
+	_, action := m.actions[state]
+	return menu || action
+}
+
+// states returns the names of all states in order
+func (m *Machine) states() []string {
+	var names []string
+	for name := range m.menus {
+		names = append(names, name)
+	}
+	for name := range m.actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedKeys(choices map[string]string) []string {
+	keys := make([]string, 0, len(choices))
+	for k := range choices {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Render returns the TwiML of a menu on the given attempt, starting from zero.  The Gather
+// is followed by a Redirect to its own action URL, which Twilio requests without digits when
+// the caller enters nothing.
+func (m *Machine) Render(state string, attempt int) (*twiml.Response, error) {
+	menu, ok := m.menus[state]
+	if !ok {
+		return nil, fmt.Errorf("Unknown menu '%s'", state)
+	}
+	res := twiml.NewResponse()
+	m.render(res, menu, attempt)
+	return res, nil
+}
+
+func (m *Machine) render(res *twiml.Response, menu *Menu, attempt int) {
+	numDigits := menu.NumDigits
+	if numDigits == 0 {
+		for digits := range menu.Choices {
+			if len(digits) > numDigits {
+				numDigits = len(digits)
+			}
+		}
+	}
+	action := m.inputURL(menu.Name, attempt)
+	g := &twiml.Gather{
+		Action:    action,
+		Method:    http.MethodPost,
+		NumDigits: numDigits,
+		Timeout:   menu.Timeout,
+	}
+	g.Add(menu.Prompt...)
+	res.Add(g, &twiml.Redirect{Method: http.MethodPost, URL: action})
+}
+
+// ServeHTTP serves the entry URL of each state at path/state and the action URL of each
+// menu at path/state/input
+func (m *Machine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != m.path && !strings.HasPrefix(r.URL.Path, m.path+"/") {
+		http.NotFound(w, r)
+		return
+	}
+	rest := r.URL.Path[len(m.path):]
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+	state := parts[0]
+	if state == "" {
+		state = m.start
+	}
+	input := len(parts) == 2 && parts[1] == "input"
+	if len(parts) > 2 || (len(parts) == 2 && !input) || !m.has(state) {
+		http.NotFound(w, r)
+		return
+	}
+	if _, menu := m.menus[state]; input && !menu {
+		// actions do not gather input
+		http.NotFound(w, r)
+		return
+	}
+
+	var call twiml.GatherActionRequest
+	if err := twiml.Bind(&call, r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var res *twiml.Response
+	var err error
+	if input {
+		res, err = m.input(r, state, &call)
+	} else {
+		res = twiml.NewResponse()
+		err = m.enter(res, r, state, &call)
+	}
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	b, err := res.Encode()
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.Write(b)
+}
+
+// enter adds the TwiML of a state to the response
+func (m *Machine) enter(res *twiml.Response, r *http.Request, state string, call *twiml.GatherActionRequest) error {
+	if menu, ok := m.menus[state]; ok {
+		m.render(res, menu, 0)
+		return nil
+	}
+	out, err := m.actions[state](r, call)
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		return fmt.Errorf("Action '%s' returned no response", state)
+	}
+	res.Add(out.Children...)
+	return nil
+}
+
+// input advances a menu with the digits gathered from the caller
+func (m *Machine) input(r *http.Request, state string, call *twiml.GatherActionRequest) (*twiml.Response, error) {
+	menu, ok := m.menus[state]
+	if !ok {
+		return nil, fmt.Errorf("Unknown menu '%s'", state)
+	}
+	res := twiml.NewResponse()
+	if next, ok := menu.Choices[call.Digits]; ok {
+		return res, m.enter(res, r, next, call)
+	}
+
+	if call.Digits == "" {
+		res.Add(menu.NoInput...)
+	} else {
+		res.Add(menu.Invalid...)
+	}
+	attempt := attempt(r.URL.Query())
+	switch {
+	case attempt < menu.Retries:
+		m.render(res, menu, attempt+1)
+	case menu.Fallback != "":
+		return res, m.enter(res, r, menu.Fallback, call)
+	default:
+		res.Add(&twiml.Hangup{})
+	}
+	return res, nil
+}
+
+// attempt returns the number of times the prompt has been repeated
+func attempt(q url.Values) int {
+	n, err := strconv.Atoi(q.Get("attempt"))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,140 @@
+package ivr
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/BTBurke/twiml"
+	"github.com/BTBurke/twiml/twimltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func dial(number string) Action {
+	return func(r *http.Request, call *twiml.GatherActionRequest) (*twiml.Response, error) {
+		res := twiml.NewResponse()
+		res.Add(&twiml.Dial{Number: number})
+		return res, nil
+	}
+}
+
+func machine() *Machine {
+	return New("/ivr", "main").
+		Menu(&Menu{
+			Name:     "main",
+			Prompt:   []twiml.Markup{&twiml.Say{Text: "Press 1 for sales or 2 for billing"}},
+			Choices:  map[string]string{"1": "sales", "2": "billing"},
+			Retries:  1,
+			NoInput:  []twiml.Markup{&twiml.Say{Text: "Sorry, I didn't hear you"}},
+			Invalid:  []twiml.Markup{&twiml.Say{Text: "That is not an option"}},
+			Fallback: "operator",
+		}).
+		Menu(&Menu{
+			Name:    "billing",
+			Prompt:  []twiml.Markup{&twiml.Say{Text: "Enter your 4 digit account number"}},
+			Choices: map[string]string{"1234": "operator"},
+		}).
+		Action("sales", dial("+15550001111")).
+		Action("operator", dial("+15550000000"))
+}
+
+func simulate(t *testing.T, inputs ...twimltest.Input) *twimltest.Result {
+	s := &twimltest.Simulator{
+		Handler: machine(),
+		URL:     "https://example.com/ivr",
+		Script:  twimltest.Script{Inputs: inputs},
+	}
+	res, err := s.Run()
+	assert.NoError(t, err)
+	return res
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, machine().Validate())
+
+	m := machine().Menu(&Menu{Name: "sales", Choices: map[string]string{"9": "nowhere"}})
+	err := m.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "sales: defined as both a menu and an action")
+	assert.Contains(t, err.Error(), "sales: unknown state 'nowhere'")
+
+	assert.Error(t, New("/ivr", "missing").Validate())
+}
+
+func TestRender(t *testing.T) {
+	res, err := machine().Render("billing", 2)
+	assert.NoError(t, err)
+	g := res.Children[0].(*twiml.Gather)
+	assert.Equal(t, "/ivr/billing/input?attempt=2", g.Action)
+	assert.Equal(t, 4, g.NumDigits)
+	assert.Equal(t, "/ivr/billing/input?attempt=2", res.Children[1].(*twiml.Redirect).URL)
+	assert.NoError(t, res.Validate())
+
+	_, err = machine().Render("sales", 0)
+	assert.Error(t, err)
+}
+
+func TestChoice(t *testing.T) {
+	res := simulate(t, twimltest.Digits("1"))
+	assert.Equal(t, []string{
+		"Say: Press 1 for sales or 2 for billing",
+		"Gather: 1",
+		"Dial: +15550001111 (completed)",
+	}, res.Lines())
+	assert.Equal(t, "https://example.com/ivr/main/input", res.Requests[1].URL)
+}
+
+func TestNestedMenu(t *testing.T) {
+	res := simulate(t, twimltest.Digits("2"), twimltest.Digits("1234"))
+	assert.Equal(t, []string{
+		"Say: Press 1 for sales or 2 for billing",
+		"Gather: 2",
+		"Say: Enter your 4 digit account number",
+		"Gather: 1234",
+		"Dial: +15550000000 (completed)",
+	}, res.Lines())
+}
+
+func TestRetriesThenFallback(t *testing.T) {
+	res := simulate(t, twimltest.NoInput, twimltest.Digits("7"))
+	assert.Equal(t, []string{
+		"Say: Press 1 for sales or 2 for billing",
+		"Gather: timeout",
+		"Redirect: /ivr/main/input",
+		"Say: Sorry, I didn't hear you",
+		"Say: Press 1 for sales or 2 for billing",
+		"Gather: 7",
+		"Say: That is not an option",
+		"Dial: +15550000000 (completed)",
+	}, res.Lines())
+}
+
+func TestHangupWithoutFallback(t *testing.T) {
+	res := simulate(t, twimltest.Digits("2"), twimltest.Digits("0000"))
+	assert.Equal(t, "Hangup", res.EndedBy)
+}
+
+func TestRoutes(t *testing.T) {
+	m := machine()
+	for _, path := range []string{"/other", "/ivrmain", "/ivr/unknown", "/ivr/main/other", "/ivr/main/input/extra", "/ivr/sales/input"} {
+		rec := twimltest.IncomingCall("+15551112222", "+15553334444").To("https://example.com" + path).Record(m)
+		assert.Equal(t, http.StatusNotFound, rec.Code, path)
+	}
+
+	rec := twimltest.IncomingCall("+15551112222", "+15553334444").To("https://example.com/ivr/billing").Record(m)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/xml", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "account number")
+
+	m.Action("sales", func(r *http.Request, call *twiml.GatherActionRequest) (*twiml.Response, error) {
+		return nil, errors.New("no agents")
+	})
+	rec = twimltest.GatherAction("1").To("https://example.com/ivr/main/input").Record(m)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	m.Action("sales", func(r *http.Request, call *twiml.GatherActionRequest) (*twiml.Response, error) {
+		return nil, nil
+	})
+	rec = twimltest.GatherAction("1").To("https://example.com/ivr/main/input").Record(m)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,97 @@
+package twiml
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Responses and verbs are serialized to JSON and YAML as objects naming the verb by its XML
+// element name, with its attributes, text and nested verbs:
+//
+//	{"verb": "Dial", "attrs": {"timeout": "15"}, "children": [{"verb": "Number", "text": "+15555555555"}]}
+//
+// Attribute values are written as strings.  Numbers and booleans are accepted when reading JSON.
+
+// UnmarshalJSON reads a node, accepting numbers and booleans as attribute values
+func (n *node) UnmarshalJSON(b []byte) error {
+	var v struct {
+		Name     string                 `json:"verb"`
+		Attrs    map[string]interface{} `json:"attrs"`
+		Text     string                 `json:"text"`
+		Children []*node                `json:"children"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	n.Name, n.Text, n.Children = v.Name, v.Text, v.Children
+	n.Attrs = make(map[string]string, len(v.Attrs))
+	for name, value := range v.Attrs {
+		switch t := value.(type) {
+		case string:
+			n.Attrs[name] = t
+		case bool:
+			n.Attrs[name] = strconv.FormatBool(t)
+		case float64:
+			n.Attrs[name] = strconv.FormatFloat(t, 'f', -1, 64)
+		default:
+			return fmt.Errorf("Invalid value for attribute '%s' on %s", name, v.Name)
+		}
+	}
+	return nil
+}
+
+func marshalJSON(m Markup) ([]byte, error) {
+	return json.Marshal(toNode(m))
+}
+
+func unmarshalJSON(m Markup, b []byte) error {
+	var n node
+	if err := json.Unmarshal(b, &n); err != nil {
+		return err
+	}
+	return setNode(m, &n)
+}
+
+func marshalYAML(m Markup) (interface{}, error) {
+	return toNode(m), nil
+}
+
+func unmarshalYAML(m Markup, unmarshal func(interface{}) error) error {
+	var n node
+	if err := unmarshal(&n); err != nil {
+		return err
+	}
+	return setNode(m, &n)
+}
+
+// setNode replaces the contents of the markup with the node, which must name the same verb
+func setNode(m Markup, n *node) error {
+	if name := elementName(m); n.Name != name {
+		return fmt.Errorf("Expected %s, found '%s'", name, n.Name)
+	}
+	v := reflect.ValueOf(m).Elem()
+	v.Set(reflect.Zero(v.Type()))
+	return setMarkup(m, n)
+}
+
+// MarshalJSON encodes the response as JSON
+func (r Response) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&r)
+}
+
+// UnmarshalJSON decodes the response from JSON
+func (r *Response) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(r, b)
+}
+
+// MarshalYAML encodes the response as YAML
+func (r Response) MarshalYAML() (interface{}, error) {
+	return marshalYAML(&r)
+}
+
+// UnmarshalYAML decodes the response from YAML
+func (r *Response) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(r, unmarshal)
+}
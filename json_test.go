@@ -0,0 +1,66 @@
+package twiml
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+var _ = Describe("JSON and YAML serialization", func() {
+	build := func() *Response {
+		r := NewResponse()
+		d := &Dial{Timeout: 15, HangupOnStar: true}
+		d.Add(&Number{Number: "+15555555555"})
+		r.Add(&Say{Voice: Alice, Text: "Connecting"}, d, &Sms{Text: "Missed call"})
+		return r
+	}
+
+	It("encodes a response as JSON", func() {
+		b, err := json.Marshal(build())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(b).To(MatchJSON(`{"verb": "Response", "children": [
+			{"verb": "Say", "attrs": {"voice": "alice"}, "text": "Connecting"},
+			{"verb": "Dial", "attrs": {"timeout": "15", "hangupOnStar": "true"}, "children": [
+				{"verb": "Number", "text": "+15555555555"}
+			]},
+			{"verb": "Message", "text": "Missed call"}
+		]}`))
+	})
+
+	It("decodes a flow authored in JSON", func() {
+		var r Response
+		err := json.Unmarshal([]byte(`{"verb": "Response", "children": [
+			{"verb": "Say", "attrs": {"voice": "alice"}, "text": "Connecting"},
+			{"verb": "Dial", "attrs": {"timeout": 15, "hangupOnStar": true}, "children": [
+				{"verb": "Number", "text": "+15555555555"}
+			]},
+			{"verb": "Message", "text": "Missed call"}
+		]}`), &r)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(r.Validate()).To(Succeed())
+		Expect(r.Children).To(Equal(build().Children))
+
+		_, err = r.Encode()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("round trips single verbs", func() {
+		b, err := json.Marshal(&Gather{Action: "/menu", NumDigits: 1, Children: []Markup{&Pause{Length: 1}}})
+		Expect(err).ToNot(HaveOccurred())
+		var g Gather
+		Expect(json.Unmarshal(b, &g)).To(Succeed())
+		Expect(g).To(Equal(Gather{Action: "/menu", NumDigits: 1, Children: []Markup{&Pause{Length: 1}}}))
+
+		Expect(json.Unmarshal(b, &Say{})).ToNot(Succeed())
+	})
+
+	It("round trips YAML", func() {
+		b, err := yaml.Marshal(build())
+		Expect(err).ToNot(HaveOccurred())
+		var r Response
+		Expect(yaml.Unmarshal(b, &r)).To(Succeed())
+		Expect(r.Children).To(Equal(build().Children))
+	})
+})
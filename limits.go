@@ -0,0 +1,117 @@
+package twiml
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Limits bounds the size of a response.  Twilio rejects responses over these limits when
+// the call is running, so they are checked when the response is validated, except for
+// MaxBytes which is checked as the response is encoded.  A zero value disables the check.
+type Limits struct {
+	// MaxBytes is the size of the encoded response, including the XML header
+	MaxBytes int
+	// MaxSayLength is the number of characters in the text of a Say
+	MaxSayLength int
+	// MaxMessageLength is the number of characters in the text of a Message
+	MaxMessageLength int
+	// MaxMedia is the number of Media in a Message
+	MaxMedia int
+}
+
+// DefaultLimits are the limits enforced by Twilio, used when a Response does not set Limits
+var DefaultLimits = Limits{
+	MaxBytes:         64 * 1024,
+	MaxSayLength:     MaxSayLength,
+	MaxMessageLength: 1600,
+	MaxMedia:         10,
+}
+
+// LimitError reports markup that is over one of the Limits
+type LimitError struct {
+	// Path is the verb over the limit.  For MaxBytes it is the innermost verb being written
+	// when the response went over the limit, or empty when only the end of the response is.
+	Path string
+	// Limit is the name of the field in Limits
+	Limit string
+	// Max is the value of the limit
+	Max int
+	// Actual is the size of the markup
+	Actual int
+}
+
+func (e LimitError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "Response"
+	}
+	return path + ": " + e.message()
+}
+
+// message describes the error without its path
+func (e LimitError) message() string {
+	var msg string
+	switch e.Limit {
+	case "MaxBytes":
+		msg = "response is %d bytes"
+	case "MaxSayLength":
+		msg = "Say text is %d characters"
+	case "MaxMessageLength":
+		msg = "Message text is %d characters"
+	case "MaxMedia":
+		msg = "Message has %d Media"
+	default:
+		msg = e.Limit + " is %d"
+	}
+	return fmt.Sprintf(msg+", over the limit of %d", e.Actual, e.Max)
+}
+
+// limits returns the limits that apply to the response
+func (r *Response) limits() Limits {
+	if r.Limits != nil {
+		return *r.Limits
+	}
+	return DefaultLimits
+}
+
+// checkLimits returns an error for each verb over the length and count limits
+func checkLimits(r *Response, l Limits) []error {
+	var errs []error
+	over := func(path string, limit string, max int, actual int) {
+		if max > 0 && actual > max {
+			errs = append(errs, LimitError{Path: path, Limit: limit, Max: max, Actual: actual})
+		}
+	}
+	Walk(r, func(path string, m Markup) error {
+		switch t := m.(type) {
+		case *Say:
+			over(path, "MaxSayLength", l.MaxSayLength, utf8.RuneCountInString(t.Text))
+		case *Sms:
+			over(path, "MaxMessageLength", l.MaxMessageLength, utf8.RuneCountInString(t.Text))
+			media := 0
+			for _, k := range t.Children {
+				if k.Type() == "Media" {
+					media++
+				}
+			}
+			over(path, "MaxMedia", l.MaxMedia, media)
+		}
+		return nil
+	})
+	return errs
+}
+
+// step is a verb being written, as its index in the markup nested in its parent
+type step struct {
+	kids []Markup
+	i    int
+}
+
+// trailPath returns the path of the verb at the end of the trail
+func trailPath(trail []step) string {
+	path := ""
+	for _, s := range trail {
+		path = kidPaths(path, s.kids)[s.i]
+	}
+	return path
+}
@@ -0,0 +1,156 @@
+package twiml
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Limits", func() {
+	limitErrors := func(err error) []error {
+		Expect(err).To(HaveOccurred())
+		ve, ok := err.(ValidationError)
+		Expect(ok).To(BeTrue())
+		return ve.Errors
+	}
+
+	It("rejects a Say over the length limit", func() {
+		r := NewResponse()
+		g := &Gather{}
+		g.Add(&Say{Text: "Press 1"}, &Say{Text: strings.Repeat("a", MaxSayLength+1)})
+		r.Add(g)
+		errs := limitErrors(r.Validate())
+		Expect(errs).To(ConsistOf(LimitError{Path: "Gather[0]/Say[1]", Limit: "MaxSayLength", Max: MaxSayLength, Actual: MaxSayLength + 1}))
+		Expect(errs[0].Error()).To(Equal("Gather[0]/Say[1]: Say text is 4097 characters, over the limit of 4096"))
+	})
+
+	It("counts characters rather than bytes", func() {
+		r := NewResponse()
+		r.Add(&Say{Text: strings.Repeat("é", MaxSayLength)})
+		Expect(r.Validate()).To(Succeed())
+	})
+
+	It("rejects a Message over the length and media limits", func() {
+		r := NewResponse()
+		m := &Sms{Text: strings.Repeat("a", 1601)}
+		for i := 0; i < 11; i++ {
+			m.Add(&Media{URL: "https://example.com/cat.jpg"})
+		}
+		r.Add(m)
+		Expect(limitErrors(r.Validate())).To(ConsistOf(
+			LimitError{Path: "Message[0]", Limit: "MaxMessageLength", Max: 1600, Actual: 1601},
+			LimitError{Path: "Message[0]", Limit: "MaxMedia", Max: 10, Actual: 11},
+		))
+	})
+
+	It("encodes media in a Message", func() {
+		r := NewResponse()
+		m := &Sms{Text: "Look"}
+		m.Add(&Media{URL: "https://example.com/cat.jpg"})
+		r.Add(m)
+		var buf bytes.Buffer
+		Expect(r.EncodeTo(&buf, EncodeOptions{Compact: true})).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("<Message>Look<Media>https://example.com/cat.jpg</Media></Message>"))
+	})
+
+	It("accepts a Message with media and no text", func() {
+		r := NewResponse()
+		m := &Sms{}
+		m.Add(&Media{URL: "https://example.com/cat.jpg"})
+		r.Add(m)
+		Expect(r.Validate()).To(Succeed())
+		var buf bytes.Buffer
+		Expect(r.EncodeTo(&buf, EncodeOptions{Compact: true})).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("<Message><Media>https://example.com/cat.jpg</Media></Message>"))
+
+		Expect((&Sms{}).Validate()).ToNot(Succeed())
+	})
+
+	Context("size", func() {
+		build := func() *Response {
+			r := NewResponse()
+			g := &Gather{Action: "/menu"}
+			g.Add(&Say{Text: "Press 1"}, &Say{Text: strings.Repeat("a", 200)})
+			r.Add(&Say{Text: "Hello"}, g, &Hangup{})
+			return r
+		}
+		size := func() int {
+			b, err := build().Encode()
+			Expect(err).ToNot(HaveOccurred())
+			return len(b)
+		}
+
+		It("accepts a response at the limit", func() {
+			r := build()
+			r.Limits = &Limits{MaxBytes: size()}
+			_, err := r.Encode()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("reports the verb that pushed the response over the limit", func() {
+			r := build()
+			r.Limits = &Limits{MaxBytes: 200}
+			_, err := r.Encode()
+			errs := limitErrors(err)
+			Expect(errs).To(ConsistOf(LimitError{Path: "Gather[0]/Say[1]", Limit: "MaxBytes", Max: 200, Actual: size()}))
+			Expect(errs[0].Error()).To(HavePrefix("Gather[0]/Say[1]: response is"))
+		})
+
+		It("reports the response when only its end is over the limit", func() {
+			r := build()
+			r.Limits = &Limits{MaxBytes: size() - 1}
+			_, err := r.Encode()
+			Expect(limitErrors(err)[0].(LimitError).Path).To(Equal(""))
+		})
+
+		It("leaves the size to the encoder", func() {
+			r := build()
+			r.Limits = &Limits{MaxBytes: 200}
+			Expect(r.Validate()).To(Succeed())
+		})
+
+		It("writes nothing when the response is too large", func() {
+			r := build()
+			r.Limits = &Limits{MaxBytes: 200}
+			b, err := r.Encode()
+			Expect(err).To(HaveOccurred())
+			Expect(b).To(BeEmpty())
+
+			buf := bytes.NewBufferString("kept")
+			Expect(r.MarshalTwiML(buf)).ToNot(Succeed())
+			Expect(buf.String()).To(Equal("kept"))
+		})
+
+		It("measures the encoding that is written", func() {
+			r := build()
+			r.Limits = &Limits{MaxBytes: size() - 10}
+			var buf bytes.Buffer
+			Expect(r.EncodeTo(&buf, EncodeOptions{Compact: true})).To(Succeed())
+			_, err := r.Encode()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("applies the default limit", func() {
+			r := NewResponse()
+			for i := 0; i < 20; i++ {
+				r.Add(&Say{Text: strings.Repeat("a", 4000)})
+			}
+			_, err := r.Encode()
+			errs := limitErrors(err)
+			Expect(errs[0].(LimitError).Path).To(Equal("Say[16]"))
+		})
+	})
+
+	It("disables checks with zero limits", func() {
+		r := NewResponse()
+		r.Limits = &Limits{}
+		for i := 0; i < 20; i++ {
+			r.Add(&Say{Text: strings.Repeat("a", 5000)})
+		}
+		Expect(r.Validate()).To(Succeed())
+		_, err := r.Encode()
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,290 @@
+package twiml
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Severity ranks how serious a lint finding is
+type Severity int
+
+// Lint finding severities
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+// String returns the name of the severity
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "error"
+	}
+}
+
+// Finding is a problem reported by a lint rule
+type Finding struct {
+	Rule     string
+	Severity Severity
+	// Path locates the verb as reported by Walk
+	Path    string
+	Message string
+}
+
+// String returns a readable representation of the finding
+func (f Finding) String() string {
+	path := f.Path
+	if path == "" {
+		path = "Response"
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", f.Severity, path, f.Message, f.Rule)
+}
+
+// ReportFunc is called by a lint rule for each problem it finds
+type ReportFunc func(path string, format string, args ...interface{})
+
+// Rule is a lint check applied to a response.  Rules report problems with TwiML that is
+// valid but unlikely to behave as intended.
+type Rule struct {
+	Name     string
+	Severity Severity
+	Check    func(r *Response, report ReportFunc)
+}
+
+// Lint checks the response against the rules provided, or DefaultRules when no rules are given,
+// and returns the findings in the order they were reported
+func Lint(r *Response, rules ...Rule) []Finding {
+	if len(rules) == 0 {
+		rules = DefaultRules
+	}
+	var findings []Finding
+	for _, rule := range rules {
+		rule.Check(r, func(path string, format string, args ...interface{}) {
+			findings = append(findings, Finding{
+				Rule:     rule.Name,
+				Severity: rule.Severity,
+				Path:     path,
+				Message:  fmt.Sprintf(format, args...),
+			})
+		})
+	}
+	return findings
+}
+
+// MaxSayLength is the maximum number of characters Twilio will speak in a single Say verb
+const MaxSayLength = 4096
+
+// DefaultRules are the rules applied by Lint when none are given
+var DefaultRules = []Rule{
+	UnreachableRule,
+	RejectFirstRule,
+	GatherActionRule,
+	RecordMaxLengthRule,
+	DialNumberRule,
+	SayLengthRule,
+	PlayLoopRule,
+	DeprecatedVoiceRule,
+	DeprecatedSmsRule,
+	TimeoutRule,
+}
+
+// UnreachableRule reports verbs after a Hangup, Redirect or Reject, which are never executed
+var UnreachableRule = Rule{
+	Name:     "unreachable",
+	Severity: SeverityWarning,
+	Check: func(r *Response, report ReportFunc) {
+		paths := kidPaths("", r.Children)
+		for i, m := range r.Children {
+			switch m.Type() {
+			case "Hangup", "Redirect", "Reject":
+				for j := i + 1; j < len(r.Children); j++ {
+					report(paths[j], "%s is unreachable after %s", r.Children[j].Type(), m.Type())
+				}
+				return
+			}
+		}
+	},
+}
+
+// RejectFirstRule reports a Reject that is not the first verb, since earlier verbs answer the
+// call and are billed
+var RejectFirstRule = Rule{
+	Name:     "reject-first",
+	Severity: SeverityWarning,
+	Check: func(r *Response, report ReportFunc) {
+		paths := kidPaths("", r.Children)
+		for i, m := range r.Children {
+			if m.Type() == "Reject" && i > 0 {
+				report(paths[i], "Reject should be the first verb or the call is answered before it is rejected")
+			}
+		}
+	},
+}
+
+// GatherActionRule reports a Gather without an action, which requests the current document again
+// with the caller's input
+var GatherActionRule = Rule{
+	Name:     "gather-action",
+	Severity: SeverityWarning,
+	Check: func(r *Response, report ReportFunc) {
+		Walk(r, func(path string, m Markup) error {
+			if g, ok := m.(*Gather); ok && g.Action == "" {
+				report(path, "Gather without an action requests the current document again")
+			}
+			return nil
+		})
+	},
+}
+
+// RecordMaxLengthRule reports a Record without a maxLength, which records for up to an hour
+var RecordMaxLengthRule = Rule{
+	Name:     "record-max-length",
+	Severity: SeverityInfo,
+	Check: func(r *Response, report ReportFunc) {
+		Walk(r, func(path string, m Markup) error {
+			if rec, ok := m.(*Record); ok && rec.MaxLength == 0 {
+				report(path, "Record without maxLength records for up to 3600 seconds")
+			}
+			return nil
+		})
+	},
+}
+
+// DialNumberRule reports a Dial with both a number and nested nouns, where the number is ignored
+var DialNumberRule = Rule{
+	Name:     "dial-number",
+	Severity: SeverityError,
+	Check: func(r *Response, report ReportFunc) {
+		Walk(r, func(path string, m Markup) error {
+			if d, ok := m.(*Dial); ok && d.Number != "" && len(d.Children) > 0 {
+				report(path, "Dial has both the number '%s' and nested nouns", d.Number)
+			}
+			return nil
+		})
+	},
+}
+
+// SayLengthRule reports a Say with more text than Twilio will speak
+var SayLengthRule = Rule{
+	Name:     "say-length",
+	Severity: SeverityError,
+	Check: func(r *Response, report ReportFunc) {
+		Walk(r, func(path string, m Markup) error {
+			if s, ok := m.(*Say); ok {
+				if n := utf8.RuneCountInString(s.Text); n > MaxSayLength {
+					report(path, "Say text is %d characters, more than the limit of %d", n, MaxSayLength)
+				}
+			}
+			return nil
+		})
+	},
+}
+
+// PlayLoopRule reports a Play with loop="0", which Twilio repeats until the call ends.  A loop
+// of zero decodes to the default, so only responses read with Decode can be checked.
+var PlayLoopRule = Rule{
+	Name:     "play-loop",
+	Severity: SeverityWarning,
+	Check: func(r *Response, report ReportFunc) {
+		if r.decoded == nil {
+			return
+		}
+		zero := make(map[string]bool)
+		zeroLoops("", r.decoded, zero)
+		Walk(r, func(path string, m Markup) error {
+			if p, ok := m.(*Play); ok && p.Loop == 0 && zero[path] {
+				report(path, "Play with loop 0 repeats until the call ends")
+			}
+			return nil
+		})
+	},
+}
+
+// zeroLoops adds the paths of Play elements with a loop attribute of zero below the node
+func zeroLoops(path string, n *node, found map[string]bool) {
+	names := make([]string, len(n.Children))
+	for i, c := range n.Children {
+		names[i] = c.Name
+	}
+	for i, p := range indexedPaths(path, names) {
+		c := n.Children[i]
+		if loop, ok := c.Attrs["loop"]; ok && c.Name == "Play" {
+			if i, err := strconv.Atoi(strings.TrimSpace(loop)); err == nil && i == 0 {
+				found[p] = true
+			}
+		}
+		zeroLoops(p, c, found)
+	}
+}
+
+// DeprecatedVoiceRule reports a Say using the man or woman voices, which Twilio has replaced
+// with Polly and Google voices
+var DeprecatedVoiceRule = Rule{
+	Name:     "deprecated-voice",
+	Severity: SeverityWarning,
+	Check: func(r *Response, report ReportFunc) {
+		Walk(r, func(path string, m Markup) error {
+			if s, ok := m.(*Say); ok && (s.Voice == Man || s.Voice == Woman) {
+				report(path, "Say voice %s is deprecated", s.Voice)
+			}
+			return nil
+		})
+	},
+}
+
+// DeprecatedSmsRule reports a Message in a response to a call.  Sending a message during a call
+// with the Sms verb is deprecated in favor of the Messages API.
+var DeprecatedSmsRule = Rule{
+	Name:     "deprecated-sms",
+	Severity: SeverityWarning,
+	Check: func(r *Response, report ReportFunc) {
+		voice := false
+		for _, m := range r.Children {
+			if m.Type() != "Sms" {
+				voice = true
+			}
+		}
+		if !voice {
+			return
+		}
+		paths := kidPaths("", r.Children)
+		for i, m := range r.Children {
+			if m.Type() == "Sms" {
+				report(paths[i], "sending a Message during a call is deprecated, use the Messages API")
+			}
+		}
+	},
+}
+
+// TimeoutRule reports timeouts outside of the range Twilio accepts or that keep a caller waiting
+// for more than a minute.  Twilio clamps a Dial timeout to between 5 and 600 seconds.
+var TimeoutRule = Rule{
+	Name:     "timeout",
+	Severity: SeverityWarning,
+	Check: func(r *Response, report ReportFunc) {
+		Walk(r, func(path string, m Markup) error {
+			switch t := m.(type) {
+			case *Dial:
+				if t.Timeout != 0 && (t.Timeout < 5 || t.Timeout > 600) {
+					report(path, "Dial timeout of %d seconds is outside of 5 to 600", t.Timeout)
+				}
+			case *Gather:
+				if t.Timeout < 0 || t.Timeout > 60 {
+					report(path, "Gather timeout of %d seconds is unusual", t.Timeout)
+				}
+			case *Record:
+				if t.Timeout < 0 || t.Timeout > 60 {
+					report(path, "Record timeout of %d seconds is unusual", t.Timeout)
+				}
+			}
+			return nil
+		})
+	},
+}
@@ -0,0 +1,81 @@
+package twiml
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Linting responses", func() {
+	It("finds nothing in a well formed response", func() {
+		r := NewResponse()
+		g := &Gather{Action: "/menu", NumDigits: 1}
+		g.Add(&Say{Text: "Press 1"})
+		r.Add(g, &Record{Action: "/voicemail", MaxLength: 60}, &Hangup{})
+		Expect(Lint(r)).To(BeEmpty())
+	})
+
+	It("reports findings with severity and path", func() {
+		d := &Dial{Number: "+15555555555"}
+		d.Add(&Client{Name: "test"})
+		r := NewResponse()
+		r.Add(
+			&Say{Text: strings.Repeat("a", MaxSayLength+1)},
+			&Reject{},
+			&Gather{},
+			&Record{},
+			&Play{URL: "hold.mp3"},
+			&Redirect{URL: "/next"},
+			d,
+		)
+		var found []string
+		for _, f := range Lint(r) {
+			found = append(found, f.String())
+		}
+		Expect(found).To(Equal([]string{
+			"warning: Gather[0]: Gather is unreachable after Reject (unreachable)",
+			"warning: Record[0]: Record is unreachable after Reject (unreachable)",
+			"warning: Play[0]: Play is unreachable after Reject (unreachable)",
+			"warning: Redirect[0]: Redirect is unreachable after Reject (unreachable)",
+			"warning: Dial[0]: Dial is unreachable after Reject (unreachable)",
+			"warning: Reject[0]: Reject should be the first verb or the call is answered before it is rejected (reject-first)",
+			"warning: Gather[0]: Gather without an action requests the current document again (gather-action)",
+			"info: Record[0]: Record without maxLength records for up to 3600 seconds (record-max-length)",
+			"error: Dial[0]: Dial has both the number '+15555555555' and nested nouns (dial-number)",
+			"error: Say[0]: Say text is 4097 characters, more than the limit of 4096 (say-length)",
+		}))
+	})
+
+	It("reports a decoded Play that loops forever", func() {
+		r, err := Decode([]byte(`<Response>
+  <Play loop="0">hold.mp3</Play>
+  <Play loop="1">ring.mp3</Play>
+  <Gather><Play loop="0">menu.mp3</Play></Gather>
+</Response>`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(Lint(r, PlayLoopRule)).To(Equal([]Finding{
+			{Path: "Play[0]", Rule: "play-loop", Severity: SeverityWarning, Message: "Play with loop 0 repeats until the call ends"},
+			{Path: "Gather[0]/Play[0]", Rule: "play-loop", Severity: SeverityWarning, Message: "Play with loop 0 repeats until the call ends"},
+		}))
+
+		built := NewResponse()
+		built.Add(&Play{URL: "hold.mp3"})
+		Expect(Lint(built, PlayLoopRule)).To(BeEmpty())
+	})
+
+	It("applies custom rules", func() {
+		noPause := Rule{
+			Name:     "no-pause",
+			Severity: SeverityInfo,
+			Check: func(r *Response, report ReportFunc) {
+				for _, m := range Find(r, func(path string, m Markup) bool { return m.Type() == "Pause" }) {
+					report("", "found %s", m.Type())
+				}
+			},
+		}
+		r := NewResponse()
+		r.Add(&Pause{Length: 1})
+		Expect(Lint(r, noPause)).To(Equal([]Finding{{Rule: "no-pause", Severity: SeverityInfo, Message: "found Pause"}}))
+	})
+})
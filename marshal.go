@@ -0,0 +1,280 @@
+package twiml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+// twimlMarshaler is implemented by markup that can be written without reflection
+type twimlMarshaler interface {
+	writeTwiML(w *twimlWriter)
+}
+
+// bufferPool holds buffers used to encode responses before they are written out
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// maxPooledBuffer is the largest buffer returned to the pool, so that one oversized response
+// does not hold on to its memory for the life of the process
+const maxPooledBuffer = 64 * 1024
+
+// getBuffer returns an empty buffer from the pool
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer returns a buffer to the pool unless it grew beyond maxPooledBuffer
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+// MarshalTwiML validates the response and writes it to buf without using reflection.  The
+// output is identical to Encode.  Nothing is written when the response fails validation.
+// Custom markup that does not support this path is encoded with encoding/xml instead.
+func (r *Response) MarshalTwiML(buf *bytes.Buffer) error {
+	_, err := r.encode(buf, DefaultEncodeOptions)
+	return err
+}
+
+// marshal writes the response to buf, returning a LimitError when it is over the MaxBytes limit
+func (r *Response) marshal(buf *bytes.Buffer, opts EncodeOptions) (*LimitError, error) {
+	start := buf.Len()
+	max := r.limits().MaxBytes
+	if !opts.OmitHeader {
+		buf.WriteString(xml.Header)
+	}
+	var over []step
+	if marshalable(r) {
+		w := &twimlWriter{buf: buf}
+		if !opts.Compact {
+			w.indent = opts.Indent
+		}
+		if max > 0 {
+			w.limit = start + max
+		}
+		r.writeTwiML(w)
+		over = w.over
+	} else {
+		enc := xml.NewEncoder(buf)
+		if !opts.Compact {
+			enc.Indent("", opts.Indent)
+		}
+		if err := enc.Encode(r); err != nil {
+			buf.Truncate(start)
+			return nil, err
+		}
+	}
+	if n := buf.Len() - start; max > 0 && n > max {
+		return &LimitError{Path: trailPath(over), Limit: "MaxBytes", Max: max, Actual: n}, nil
+	}
+	return nil, nil
+}
+
+// encodeTo writes the response to w through a pooled buffer
+func (r *Response) encodeTo(w io.Writer, opts EncodeOptions) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if _, err := r.encode(buf, opts); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// marshalable reports whether every verb in the tree can be written without reflection
+func marshalable(m Markup) bool {
+	if _, ok := m.(twimlMarshaler); !ok {
+		return false
+	}
+	if c, ok := m.(Container); ok {
+		for _, k := range c.Kids() {
+			if !marshalable(k) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// twimlWriter writes XML elements with the same indentation and escaping as encoding/xml
+type twimlWriter struct {
+	buf        *bytes.Buffer
+	indent     string
+	depth      int
+	indentedIn bool
+	putNewline bool
+
+	// limit is the length of buf after which over records the verbs being written
+	limit int
+	trail []step
+	over  []step
+}
+
+// writeIndent follows the indentation rules of the encoding/xml printer, which only breaks
+// the line before an end element if the element contains other elements
+func (w *twimlWriter) writeIndent(depthDelta int) {
+	if w.indent == "" {
+		return
+	}
+	if depthDelta < 0 {
+		w.depth--
+		if w.indentedIn {
+			w.indentedIn = false
+			return
+		}
+		w.indentedIn = false
+	}
+	if w.putNewline {
+		w.buf.WriteByte('\n')
+	} else {
+		w.putNewline = true
+	}
+	for i := 0; i < w.depth; i++ {
+		w.buf.WriteString(w.indent)
+	}
+	if depthDelta > 0 {
+		w.depth++
+		w.indentedIn = true
+	}
+}
+
+// start opens an element, which must be followed by attributes and closeStart
+func (w *twimlWriter) start(name string) {
+	w.writeIndent(1)
+	w.buf.WriteByte('<')
+	w.buf.WriteString(name)
+}
+
+func (w *twimlWriter) closeStart() {
+	w.buf.WriteByte('>')
+}
+
+// attr writes a string attribute, omitting the empty string
+func (w *twimlWriter) attr(name string, value string) {
+	if value == "" {
+		return
+	}
+	w.buf.WriteByte(' ')
+	w.buf.WriteString(name)
+	w.buf.WriteString(`="`)
+	w.escape(value)
+	w.buf.WriteByte('"')
+}
+
+// attrInt writes an int attribute, omitting zero
+func (w *twimlWriter) attrInt(name string, value int) {
+	if value == 0 {
+		return
+	}
+	w.attr(name, strconv.Itoa(value))
+}
+
+// attrBool writes a bool attribute, omitting false
+func (w *twimlWriter) attrBool(name string, value bool) {
+	if value {
+		w.attr(name, "true")
+	}
+}
+
+func (w *twimlWriter) text(s string) {
+	w.escape(s)
+}
+
+// escape writes s with the same escaping as xml.EscapeText, without converting it to a
+// byte slice first
+func (w *twimlWriter) escape(s string) {
+	last := 0
+	for i := 0; i < len(s); {
+		r, width := utf8.DecodeRuneInString(s[i:])
+		i += width
+		var esc string
+		switch r {
+		case '"':
+			esc = "&#34;"
+		case '\'':
+			esc = "&#39;"
+		case '&':
+			esc = "&amp;"
+		case '<':
+			esc = "&lt;"
+		case '>':
+			esc = "&gt;"
+		case '\t':
+			esc = "&#x9;"
+		case '\n':
+			esc = "&#xA;"
+		case '\r':
+			esc = "&#xD;"
+		default:
+			if !isInCharacterRange(r) || (r == utf8.RuneError && width == 1) {
+				esc = "\uFFFD"
+				break
+			}
+			continue
+		}
+		w.buf.WriteString(s[last : i-width])
+		w.buf.WriteString(esc)
+		last = i
+	}
+	w.buf.WriteString(s[last:])
+}
+
+// isInCharacterRange reports whether r is allowed in XML character data
+func isInCharacterRange(r rune) bool {
+	return r == 0x09 ||
+		r == 0x0A ||
+		r == 0x0D ||
+		r >= 0x20 && r <= 0xD7FF ||
+		r >= 0xE000 && r <= 0xFFFD ||
+		r >= 0x10000 && r <= 0x10FFFF
+}
+
+// textElement writes an element containing only text, omitting the empty string
+func (w *twimlWriter) textElement(name string, s string) {
+	if s == "" {
+		return
+	}
+	w.start(name)
+	w.closeStart()
+	w.text(s)
+	w.end(name)
+}
+
+func (w *twimlWriter) children(ml []Markup) {
+	for i, m := range ml {
+		if w.limit > 0 {
+			w.trail = append(w.trail, step{ml, i})
+		}
+		m.(twimlMarshaler).writeTwiML(w)
+		if w.limit > 0 {
+			w.trail = w.trail[:len(w.trail)-1]
+		}
+	}
+}
+
+func (w *twimlWriter) end(name string) {
+	w.writeIndent(-1)
+	w.buf.WriteString("</")
+	w.buf.WriteString(name)
+	w.buf.WriteByte('>')
+	if w.limit > 0 && w.over == nil && w.buf.Len() > w.limit {
+		w.over = append([]step{}, w.trail...)
+	}
+}
+
+func (r *Response) writeTwiML(w *twimlWriter) {
+	w.start("Response")
+	w.closeStart()
+	w.children(r.Children)
+	w.end("Response")
+}
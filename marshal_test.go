@@ -0,0 +1,174 @@
+package twiml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// xmlEncode encodes a response with encoding/xml, the reference for the fast path
+func xmlEncode(r *Response, opts EncodeOptions) []byte {
+	var buf bytes.Buffer
+	if !opts.OmitHeader {
+		buf.WriteString(xml.Header)
+	}
+	enc := xml.NewEncoder(&buf)
+	if !opts.Compact {
+		enc.Indent("", opts.Indent)
+	}
+	if err := enc.Encode(r); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+func simpleTree() *Response {
+	r := NewResponse()
+	r.Add(&Say{Text: "Hello", Voice: Alice, Language: "en-US"}, &Hangup{})
+	return r
+}
+
+func menuTree() *Response {
+	r := NewResponse()
+	g := &Gather{Action: "/menu?a=1&b=2", NumDigits: 1, Timeout: 5, Input: "dtmf speech"}
+	g.Add(&Say{Text: `Press 1 for "sales" & 2 for <support>`, Loop: 2}, &Pause{Length: 1}, &Play{URL: "https://example.com/a.mp3", Digits: "ww1"})
+	r.Add(g, &Say{Text: "Goodbye\n\tfor 'now' \x00\xff \u00e9\r"}, &Redirect{Method: "POST", URL: "/menu"})
+	return r
+}
+
+func dialTree() *Response {
+	r := NewResponse()
+	d := &Dial{Action: "/dial", Timeout: 20, TimeLimit: 600, AnswerOnBridge: true, HangupOnStar: true, CallerID: "+15555555555", Record: "record-from-answer"}
+	c := &Client{Name: "jenny", Identity: "jenny", Method: "POST", URL: "/whisper"}
+	c.Add(&Parameter{Name: "FirstName", Value: "Jenny"}, Parameter{Name: "LastName", Value: "Smith"})
+	d.Add(
+		&Number{Number: "+15555555556", SendDigits: "1928", URL: "/screen"},
+		c,
+		&Sip{Address: "sip:jack@example.com", Username: "admin", Password: "1234"},
+	)
+	conf := &Dial{}
+	conf.Add(&Conference{ConferenceName: "Room 1234", Muted: true, StartConferenceOnEnter: true, MaxParticipants: 10, WaitURL: "/wait"})
+	queue := &Dial{}
+	queue.Add(&Queue{Name: "support", URL: "/about", ReservationSid: "WR123"})
+	r.Add(d, conf, queue, &Record{MaxLength: 30, PlayBeep: true, Transcribe: true, FinishOnKey: "#"},
+		&Sms{Text: "Missed call", To: "+15555555557"}, &Enqueue{QueueName: "support", WaitURL: "/hold"},
+		&Leave{}, &Reject{Reason: "busy"})
+	return r
+}
+
+var _ = Describe("Fast encoder", func() {
+	trees := map[string]func() *Response{
+		"simple": simpleTree,
+		"menu":   menuTree,
+		"dial":   dialTree,
+	}
+	options := map[string]EncodeOptions{
+		"default":   DefaultEncodeOptions,
+		"tabs":      {Indent: "\t"},
+		"compact":   {Compact: true, Indent: "  "},
+		"no header": {OmitHeader: true, Indent: "  "},
+	}
+
+	for tn, tree := range trees {
+		for on, opts := range options {
+			tree, opts := tree, opts
+			It("matches encoding/xml for the "+tn+" tree with "+on+" options", func() {
+				r := tree()
+				Expect(marshalable(r)).To(BeTrue())
+				var buf bytes.Buffer
+				Expect(r.EncodeTo(&buf, opts)).To(Succeed())
+				Expect(buf.String()).To(Equal(string(xmlEncode(r, opts))))
+			})
+		}
+	}
+
+	It("writes the same output as Encode", func() {
+		r := menuTree()
+		var buf bytes.Buffer
+		Expect(r.MarshalTwiML(&buf)).To(Succeed())
+		b, err := r.Encode()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buf.Bytes()).To(Equal(b))
+		Expect(b).To(Equal(xmlEncode(r, DefaultEncodeOptions)))
+	})
+
+	It("validates before writing", func() {
+		r := NewResponse()
+		r.Add(&Say{Voice: "robot", Text: "Hi"})
+		var buf bytes.Buffer
+		Expect(r.MarshalTwiML(&buf)).ToNot(Succeed())
+		Expect(buf.Len()).To(Equal(0))
+	})
+
+	It("does not pool oversized buffers", func() {
+		big := bytes.NewBuffer(make([]byte, 0, 2*maxPooledBuffer))
+		putBuffer(big)
+		Expect(getBuffer()).ToNot(BeIdenticalTo(big))
+	})
+
+	It("falls back to encoding/xml for custom markup", func() {
+		r := NewResponse()
+		r.Add(&Say{Text: "Hi"}, &testMarkup{Name: "Custom"})
+		Expect(marshalable(r)).To(BeFalse())
+		var buf bytes.Buffer
+		over, err := r.marshal(&buf, DefaultEncodeOptions)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(over).To(BeNil())
+		Expect(buf.String()).To(Equal(string(xmlEncode(r, DefaultEncodeOptions))))
+	})
+})
+
+// testMarkup is markup without a fast path
+type testMarkup struct {
+	XMLName xml.Name `xml:"Custom"`
+	Name    string   `xml:"name,attr"`
+}
+
+func (t *testMarkup) Type() string    { return "Custom" }
+func (t *testMarkup) Validate() error { return nil }
+
+func benchmarkTrees() map[string]*Response {
+	return map[string]*Response{
+		"Simple": simpleTree(),
+		"Menu":   menuTree(),
+		"Dial":   dialTree(),
+	}
+}
+
+func BenchmarkEncodeXML(b *testing.B) {
+	for name, r := range benchmarkTrees() {
+		r := r
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			var buf bytes.Buffer
+			for i := 0; i < b.N; i++ {
+				buf.Reset()
+				buf.WriteString(xml.Header)
+				enc := xml.NewEncoder(&buf)
+				enc.Indent("", DefaultEncodeOptions.Indent)
+				if err := enc.Encode(r); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMarshalTwiML(b *testing.B) {
+	for name, r := range benchmarkTrees() {
+		r := r
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			var buf bytes.Buffer
+			for i := 0; i < b.N; i++ {
+				buf.Reset()
+				if _, err := r.marshal(&buf, DefaultEncodeOptions); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package twiml
+
+import (
+	"bytes"
+	"strings"
+)
+
+// ValidationMode controls whether validation errors stop a response from being encoded
+type ValidationMode int
+
+// Validation modes
+const (
+	// ValidateStrict refuses to encode a response that fails validation.  It is the default.
+	ValidateStrict ValidationMode = iota
+	// ValidateWarn encodes a response that fails validation, reporting each error as a warning
+	// from EncodeWithReport
+	ValidateWarn
+	// ValidateOff encodes a response without validating it or checking for warnings
+	ValidateOff
+)
+
+// WarningRules are the checks reported as warnings by EncodeWithReport
+var WarningRules = []Rule{
+	DeprecatedVoiceRule,
+	DeprecatedSmsRule,
+	TimeoutRule,
+}
+
+// Report lists the problems found in a response that did not stop it from being encoded
+type Report struct {
+	Warnings []Finding
+}
+
+// EncodeWithReport returns the XML encoded response like Encode, along with the findings of
+// WarningRules.  In ValidateWarn mode validation errors are added to the warnings and do not
+// stop the response from being encoded.
+func (r *Response) EncodeWithReport() ([]byte, Report, error) {
+	var buf bytes.Buffer
+	warnings, err := r.encode(&buf, DefaultEncodeOptions)
+	if err != nil {
+		return nil, Report{}, err
+	}
+	if r.mode() != ValidateOff {
+		warnings = append(warnings, Lint(r, WarningRules...)...)
+	}
+	return buf.Bytes(), Report{Warnings: warnings}, nil
+}
+
+// mode returns the validation mode of the response, honoring IgnoreValidationErrors
+func (r *Response) mode() ValidationMode {
+	if r.Validation == ValidateStrict && r.IgnoreValidationErrors {
+		return ValidateWarn
+	}
+	return r.Validation
+}
+
+// encode validates the response according to its mode and writes it to buf.  Validation
+// errors that did not stop the response from being written are returned as warnings.
+func (r *Response) encode(buf *bytes.Buffer, opts EncodeOptions) ([]Finding, error) {
+	mode := r.mode()
+	var warnings []Finding
+	if mode != ValidateOff {
+		if err := r.validate(); err != nil {
+			if mode == ValidateStrict {
+				return nil, err
+			}
+			warnings = validationFindings(r, err)
+		}
+	}
+	start := buf.Len()
+	over, err := r.marshal(buf, opts)
+	if err != nil {
+		return nil, err
+	}
+	if over != nil {
+		switch mode {
+		case ValidateStrict:
+			buf.Truncate(start)
+			return nil, ValidationError{[]error{*over}}
+		case ValidateWarn:
+			warnings = append(warnings, limitFinding(*over))
+		}
+	}
+	return warnings, nil
+}
+
+// validateFinding reports a validation error of the verb at path
+func validateFinding(path string, err error) Finding {
+	return Finding{Rule: "validate", Severity: SeverityError, Path: path, Message: leafMessage(err)}
+}
+
+// validationFindings converts a validation error of the response into findings for the
+// innermost failing verbs, since a verb that nests markup fails whenever its children do
+func validationFindings(r *Response, err error) []Finding {
+	var failed []Finding
+	Walk(r, func(path string, m Markup) error {
+		if path == "" {
+			return nil
+		}
+		if err := m.Validate(); err != nil {
+			failed = append(failed, validateFinding(path, err))
+		}
+		return nil
+	})
+
+	var findings []Finding
+	for i, f := range failed {
+		nested := false
+		for _, g := range failed[i+1:] {
+			if strings.HasPrefix(g.Path, f.Path+"/") {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			findings = append(findings, f)
+		}
+	}
+
+	// errors of the response itself, such as unknown verbs or verbs over the limits
+	if ve, ok := err.(ValidationError); ok && len(findings) == 0 {
+		for _, e := range ve.Errors {
+			if le, ok := e.(LimitError); ok {
+				findings = append(findings, limitFinding(le))
+			}
+		}
+	}
+	if len(findings) == 0 {
+		findings = append(findings, validateFinding("", err))
+	}
+	return findings
+}
+
+func limitFinding(e LimitError) Finding {
+	return Finding{Rule: "limits", Severity: SeverityError, Path: e.Path, Message: e.message()}
+}
+
+// leafMessage returns the message of a validation error without the headers of the
+// ValidationErrors wrapping it.  A verb reports its own error after those of its children.
+func leafMessage(err error) string {
+	for {
+		ve, ok := err.(ValidationError)
+		if !ok || len(ve.Errors) == 0 {
+			return err.Error()
+		}
+		err = ve.Errors[len(ve.Errors)-1]
+	}
+}
@@ -0,0 +1,114 @@
+package twiml
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Encoding with a report", func() {
+	invalid := func() *Response {
+		r := NewResponse()
+		g := &Gather{Action: "/menu"}
+		g.Add(&Say{Text: "Press 1", Voice: "robot"})
+		r.Add(g, &Hangup{})
+		return r
+	}
+
+	It("refuses to encode an invalid response in strict mode", func() {
+		r := invalid()
+		_, _, err := r.EncodeWithReport()
+		Expect(err).To(HaveOccurred())
+		b, err := r.Encode()
+		Expect(err).To(HaveOccurred())
+		Expect(b).To(BeEmpty())
+	})
+
+	It("reports validation errors as warnings in warn mode", func() {
+		r := invalid()
+		r.Validation = ValidateWarn
+		b, report, err := r.EncodeWithReport()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring(`<Say voice="robot">Press 1</Say>`))
+		Expect(report.Warnings).To(Equal([]Finding{
+			{Rule: "validate", Severity: SeverityError, Path: "Gather[0]/Say[0]", Message: "Say markup failed validation"},
+		}))
+
+		b, err = r.Encode()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(b).ToNot(BeEmpty())
+		Expect(r.Validate()).ToNot(Succeed())
+	})
+
+	It("treats IgnoreValidationErrors as warn mode", func() {
+		r := invalid()
+		r.IgnoreValidationErrors = true
+		_, report, err := r.EncodeWithReport()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Warnings).To(HaveLen(1))
+	})
+
+	It("reports errors of the response itself", func() {
+		r := NewResponse()
+		r.Validation = ValidateWarn
+		b, report, err := r.EncodeWithReport()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring("<Response></Response>"))
+		Expect(report.Warnings).To(Equal([]Finding{
+			{Rule: "validate", Severity: SeverityError, Message: "Can not encode an empty response"},
+		}))
+	})
+
+	It("reports limits as warnings in warn mode", func() {
+		r := NewResponse()
+		r.Validation = ValidateWarn
+		r.Limits = &Limits{MaxBytes: 50, MaxSayLength: 10}
+		r.Add(&Say{Text: strings.Repeat("a", 100)})
+		b, report, err := r.EncodeWithReport()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(b).ToNot(BeEmpty())
+		Expect(report.Warnings).To(ConsistOf(
+			Finding{Rule: "limits", Severity: SeverityError, Path: "Say[0]", Message: "Say text is 100 characters, over the limit of 10"},
+			Finding{Rule: "limits", Severity: SeverityError, Path: "Say[0]", Message: "response is 175 bytes, over the limit of 50"},
+		))
+	})
+
+	It("skips validation and warnings when off", func() {
+		r := invalid()
+		r.Validation = ValidateOff
+		r.Add(&Say{Text: "Bye", Voice: Man})
+		b, report, err := r.EncodeWithReport()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(b).ToNot(BeEmpty())
+		Expect(report.Warnings).To(BeEmpty())
+	})
+
+	It("returns warnings for a valid response", func() {
+		r := NewResponse()
+		r.Add(
+			&Say{Text: "Hello", Voice: Man},
+			&Dial{Number: "+15555555555", Timeout: 900},
+			&Sms{Text: "Sorry we missed you"},
+		)
+		_, report, err := r.EncodeWithReport()
+		Expect(err).ToNot(HaveOccurred())
+		var found []string
+		for _, f := range report.Warnings {
+			found = append(found, f.String())
+		}
+		Expect(found).To(Equal([]string{
+			"warning: Say[0]: Say voice man is deprecated (deprecated-voice)",
+			"warning: Message[0]: sending a Message during a call is deprecated, use the Messages API (deprecated-sms)",
+			"warning: Dial[0]: Dial timeout of 900 seconds is outside of 5 to 600 (timeout)",
+		}))
+	})
+
+	It("does not warn about messages in a messaging response", func() {
+		r := NewResponse()
+		r.Add(&Sms{Text: "Thanks"})
+		_, report, err := r.EncodeWithReport()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Warnings).To(BeEmpty())
+	})
+})
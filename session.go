@@ -0,0 +1,296 @@
+package twiml
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrNoSession is returned by a SessionStore when there is no session for a call
+var ErrNoSession = errors.New("No session for call")
+
+// SessionStore saves the encoded session of each call between callbacks.  Implementations
+// must be safe for concurrent use and may keep sessions in any backend, such as a database
+// or cache shared by several servers.
+type SessionStore interface {
+	// Get returns the session saved for the call, or ErrNoSession when there is none or it
+	// has expired
+	Get(ctx context.Context, callSid string) ([]byte, error)
+	// Set saves the session for the call, replacing any saved before, until ttl has passed
+	Set(ctx context.Context, callSid string, data []byte, ttl time.Duration) error
+	// Delete removes the session for the call
+	Delete(ctx context.Context, callSid string) error
+}
+
+// MemoryStore is a SessionStore that keeps sessions in memory.  Sessions are lost when the
+// process exits and are not shared between servers.
+type MemoryStore struct {
+	mu       sync.Mutex
+	sessions map[string]memorySession
+	now      func() time.Time
+}
+
+type memorySession struct {
+	data    []byte
+	expires time.Time
+}
+
+// NewMemoryStore returns an empty in-memory session store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		sessions: make(map[string]memorySession),
+		now:      time.Now,
+	}
+}
+
+// Get returns the session saved for the call
+func (m *MemoryStore) Get(ctx context.Context, callSid string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[callSid]
+	if !ok {
+		return nil, ErrNoSession
+	}
+	if !m.now().Before(s.expires) {
+		delete(m.sessions, callSid)
+		return nil, ErrNoSession
+	}
+	return s.data, nil
+}
+
+// Set saves the session for the call until ttl has passed
+func (m *MemoryStore) Set(ctx context.Context, callSid string, data []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[callSid] = memorySession{data: data, expires: m.now().Add(ttl)}
+	return nil
+}
+
+// Delete removes the session for the call
+func (m *MemoryStore) Delete(ctx context.Context, callSid string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, callSid)
+	return nil
+}
+
+// Sweep removes expired sessions.  Sessions are removed when their call ends, so it only
+// needs to run occasionally to remove sessions of calls whose final callback was missed.
+func (m *MemoryStore) Sweep() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	for sid, s := range m.sessions {
+		if !now.Before(s.expires) {
+			delete(m.sessions, sid)
+		}
+	}
+}
+
+// Len returns the number of sessions in the store, including expired sessions not yet removed
+func (m *MemoryStore) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sessions)
+}
+
+// CallSession holds values saved across the callbacks of a call.  Values are encoded as JSON
+// so they can be saved in any SessionStore.
+type CallSession struct {
+	callSid string
+	values  map[string]json.RawMessage
+	changed bool
+}
+
+// CallSid returns the call the session belongs to
+func (s *CallSession) CallSid() string {
+	return s.callSid
+}
+
+// Get decodes the value saved under key into v, reporting whether the key was found
+func (s *CallSession) Get(key string, v interface{}) (bool, error) {
+	raw, ok := s.values[key]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(raw, v)
+}
+
+// Set saves v under key
+func (s *CallSession) Set(key string, v interface{}) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	s.values[key] = raw
+	s.changed = true
+	return nil
+}
+
+// Delete removes the value saved under key
+func (s *CallSession) Delete(key string) {
+	if _, ok := s.values[key]; ok {
+		delete(s.values, key)
+		s.changed = true
+	}
+}
+
+type sessionKey struct{}
+
+// Session returns the session of the call from the context of a request handled by the
+// Sessions middleware.  When the request has no CallSid or was not handled by Sessions, it
+// returns an empty session that is not saved.
+func Session(ctx context.Context) *CallSession {
+	if s, ok := ctx.Value(sessionKey{}).(*CallSession); ok {
+		return s
+	}
+	return &CallSession{values: make(map[string]json.RawMessage)}
+}
+
+// CallEnded reports whether a call status is final, after which there are no more callbacks
+// for the call
+func CallEnded(status string) bool {
+	switch status {
+	case Completed, Busy, Failed, NoAnswer, Canceled:
+		return true
+	}
+	return false
+}
+
+// callLocks serializes the callbacks of each call
+type callLocks struct {
+	mu    sync.Mutex
+	calls map[string]*callLock
+}
+
+type callLock struct {
+	sync.Mutex
+	waiting int
+}
+
+// lock waits for other callbacks of the call to finish and returns the function that
+// releases the call
+func (l *callLocks) lock(callSid string) func() {
+	l.mu.Lock()
+	c, ok := l.calls[callSid]
+	if !ok {
+		c = &callLock{}
+		l.calls[callSid] = c
+	}
+	c.waiting++
+	l.mu.Unlock()
+
+	c.Lock()
+	return func() {
+		c.Unlock()
+		l.mu.Lock()
+		if c.waiting--; c.waiting == 0 {
+			delete(l.calls, callSid)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// Sessions returns middleware that loads the session of the call into the request context
+// before each callback, for handlers to read with Session.  Changes are saved for ttl before
+// the response is written, so they are visible to the next callback of the call.  The session
+// is deleted before the response to a callback with a CallStatus that ends the call, and the
+// callback fails when it can not be saved or deleted.
+//
+// Callbacks of the same call are handled one at a time, so a status callback that arrives
+// while an action is handled does not overwrite its changes.  Servers sharing a store do not
+// coordinate, and the last callback of a call to save its session wins.
+func Sessions(store SessionStore, ttl time.Duration) func(http.Handler) http.Handler {
+	locks := &callLocks{calls: make(map[string]*callLock)}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			values, err := requestValues(r)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			callSid := values.Get("CallSid")
+			if callSid == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			unlock := locks.lock(callSid)
+			defer unlock()
+
+			ctx := r.Context()
+			s := &CallSession{callSid: callSid, values: make(map[string]json.RawMessage)}
+			data, err := store.Get(ctx, callSid)
+			switch err {
+			case nil:
+				if err := json.Unmarshal(data, &s.values); err != nil {
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					return
+				}
+			case ErrNoSession:
+			default:
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+
+			save := func() error {
+				if !s.changed {
+					return nil
+				}
+				data, err := json.Marshal(s.values)
+				if err != nil {
+					return err
+				}
+				return store.Set(ctx, callSid, data, ttl)
+			}
+			if CallEnded(values.Get("CallStatus")) {
+				save = func() error {
+					return store.Delete(ctx, callSid)
+				}
+			}
+
+			sw := &sessionWriter{ResponseWriter: w, save: save}
+			next.ServeHTTP(sw, r.WithContext(context.WithValue(ctx, sessionKey{}, s)))
+			if !sw.saved {
+				sw.flush()
+			}
+		})
+	}
+}
+
+// sessionWriter saves or deletes the session before the response is written.  When it can
+// not, the handler's response is replaced with an error.
+type sessionWriter struct {
+	http.ResponseWriter
+	save   func() error
+	saved  bool
+	failed bool
+}
+
+// flush saves the session once, reporting whether the response can be written
+func (w *sessionWriter) flush() bool {
+	if !w.saved {
+		w.saved = true
+		if err := w.save(); err != nil {
+			w.failed = true
+			http.Error(w.ResponseWriter, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+	}
+	return !w.failed
+}
+
+func (w *sessionWriter) WriteHeader(status int) {
+	if w.flush() {
+		w.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (w *sessionWriter) Write(b []byte) (int, error) {
+	if !w.flush() {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
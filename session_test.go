@@ -0,0 +1,192 @@
+package twiml
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// failingStore is a SessionStore whose saves and deletes fail
+type failingStore struct {
+	*MemoryStore
+}
+
+func (f failingStore) Set(ctx context.Context, callSid string, data []byte, ttl time.Duration) error {
+	return errors.New("store unavailable")
+}
+
+func (f failingStore) Delete(ctx context.Context, callSid string) error {
+	return errors.New("store unavailable")
+}
+
+var _ = Describe("Sessions", func() {
+	callback := func(params url.Values) *http.Request {
+		req := httptest.NewRequest("POST", "/voice", strings.NewReader(params.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+
+	Context("memory store", func() {
+		It("expires sessions after their ttl", func() {
+			ctx := context.Background()
+			m := NewMemoryStore()
+			now := time.Now()
+			m.now = func() time.Time { return now }
+			Expect(m.Set(ctx, "CA1", []byte("one"), time.Minute)).To(Succeed())
+			Expect(m.Set(ctx, "CA2", []byte("two"), time.Hour)).To(Succeed())
+
+			data, err := m.Get(ctx, "CA1")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(Equal("one"))
+
+			now = now.Add(2 * time.Minute)
+			_, err = m.Get(ctx, "CA1")
+			Expect(err).To(Equal(ErrNoSession))
+			Expect(m.Len()).To(Equal(1))
+
+			now = now.Add(2 * time.Hour)
+			m.Sweep()
+			Expect(m.Len()).To(Equal(0))
+		})
+
+		It("deletes sessions", func() {
+			ctx := context.Background()
+			m := NewMemoryStore()
+			Expect(m.Set(ctx, "CA1", []byte("one"), time.Minute)).To(Succeed())
+			Expect(m.Delete(ctx, "CA1")).To(Succeed())
+			_, err := m.Get(ctx, "CA1")
+			Expect(err).To(Equal(ErrNoSession))
+		})
+	})
+
+	It("keeps values across the callbacks of a call", func() {
+		store := NewMemoryStore()
+		var visits []int
+		h := Sessions(store, time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var vr VoiceRequest
+			Expect(Bind(&vr, r)).To(Succeed())
+			s := Session(r.Context())
+			Expect(s.CallSid()).To(Equal(vr.CallSid))
+			var n int
+			_, err := s.Get("visits", &n)
+			Expect(err).ToNot(HaveOccurred())
+			visits = append(visits, n)
+			Expect(s.Set("visits", n+1)).To(Succeed())
+			w.Write([]byte("<Response></Response>"))
+		}))
+
+		for _, status := range []string{Ringing, InProgress, InProgress} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, callback(url.Values{"CallSid": {"CA1"}, "CallStatus": {status}}))
+			Expect(w.Code).To(Equal(200))
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, callback(url.Values{"CallSid": {"CA2"}, "CallStatus": {Ringing}}))
+
+		Expect(visits).To(Equal([]int{0, 1, 2, 0}))
+		Expect(store.Len()).To(Equal(2))
+	})
+
+	It("deletes the session when the call ends", func() {
+		store := NewMemoryStore()
+		var seen string
+		h := Sessions(store, time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s := Session(r.Context())
+			if r.FormValue("CallStatus") == Completed {
+				_, err := s.Get("menu", &seen)
+				Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			Expect(s.Set("menu", "billing")).To(Succeed())
+		}))
+
+		h.ServeHTTP(httptest.NewRecorder(), callback(url.Values{"CallSid": {"CA1"}, "CallStatus": {InProgress}}))
+		Expect(store.Len()).To(Equal(1))
+		h.ServeHTTP(httptest.NewRecorder(), callback(url.Values{"CallSid": {"CA1"}, "CallStatus": {Completed}}))
+		Expect(seen).To(Equal("billing"))
+		Expect(store.Len()).To(Equal(0))
+	})
+
+	It("reads the CallSid of JSON callbacks without consuming the body", func() {
+		h := Sessions(NewMemoryStore(), time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var vr VoiceRequest
+			Expect(Bind(&vr, r)).To(Succeed())
+			Expect(vr.CallSid).To(Equal("CA1"))
+			Expect(Session(r.Context()).CallSid()).To(Equal("CA1"))
+		}))
+		req := httptest.NewRequest("POST", "/voice", strings.NewReader(`{"CallSid": "CA1", "CallStatus": "ringing"}`))
+		req.Header.Set("Content-Type", "application/json")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	})
+
+	It("has an unsaved session without a CallSid", func() {
+		store := NewMemoryStore()
+		called := false
+		h := Sessions(store, time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			s := Session(r.Context())
+			Expect(s.CallSid()).To(BeEmpty())
+			Expect(s.Set("menu", "billing")).To(Succeed())
+		}))
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/health", nil))
+		Expect(called).To(BeTrue())
+		Expect(store.Len()).To(Equal(0))
+	})
+
+	It("handles the callbacks of a call one at a time", func() {
+		store := NewMemoryStore()
+		h := Sessions(store, time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s := Session(r.Context())
+			var n int
+			s.Get("visits", &n)
+			time.Sleep(time.Millisecond)
+			s.Set("visits", n+1)
+		}))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				h.ServeHTTP(httptest.NewRecorder(), callback(url.Values{"CallSid": {"CA1"}, "CallStatus": {InProgress}}))
+			}()
+		}
+		wg.Wait()
+
+		data, err := store.Get(context.Background(), "CA1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal(`{"visits":10}`))
+	})
+
+	It("fails the callback when the session can not be saved or deleted", func() {
+		h := Sessions(failingStore{NewMemoryStore()}, time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(Session(r.Context()).Set("menu", "billing")).To(Succeed())
+			w.Write([]byte("<Response></Response>"))
+		}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, callback(url.Values{"CallSid": {"CA1"}, "CallStatus": {InProgress}}))
+		Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		Expect(w.Body.String()).ToNot(ContainSubstring("<Response>"))
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, callback(url.Values{"CallSid": {"CA1"}, "CallStatus": {Completed}}))
+		Expect(w.Code).To(Equal(http.StatusInternalServerError))
+	})
+
+	It("reports which call statuses end a call", func() {
+		for _, s := range []string{Completed, Busy, Failed, NoAnswer, Canceled} {
+			Expect(CallEnded(s)).To(BeTrue(), s)
+		}
+		for _, s := range []string{Queued, Ringing, InProgress, ""} {
+			Expect(CallEnded(s)).To(BeFalse(), s)
+		}
+	})
+})
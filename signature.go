@@ -0,0 +1,53 @@
+package twiml
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// SignatureHeader is the request header Twilio uses to sign callback requests
+const SignatureHeader = "X-Twilio-Signature"
+
+// ErrInvalidSignature is returned when a request signature does not match the request
+var ErrInvalidSignature = errors.New("Invalid Twilio request signature")
+
+// Signature computes the signature Twilio sends in the X-Twilio-Signature header for a
+// request to rawURL with the POST parameters provided
+func Signature(authToken string, rawURL string, params url.Values) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	mac := hmac.New(sha1.New, []byte(authToken))
+	mac.Write([]byte(rawURL))
+	for _, key := range keys {
+		for _, value := range params[key] {
+			mac.Write([]byte(key + value))
+		}
+	}
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks the X-Twilio-Signature header of a form encoded request.  The
+// rawURL must be the full URL Twilio requested, which may differ from r.URL behind a proxy.
+func VerifySignature(authToken string, rawURL string, r *http.Request) error {
+	var params url.Values
+	if r.Method != http.MethodGet {
+		if err := r.ParseForm(); err != nil {
+			return err
+		}
+		params = r.PostForm
+	}
+	expected := Signature(authToken, rawURL, params)
+	if !hmac.Equal([]byte(expected), []byte(r.Header.Get(SignatureHeader))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
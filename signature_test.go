@@ -0,0 +1,33 @@
+package twiml
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Request signatures", func() {
+	rawURL := "https://mycompany.com/myapp.php?foo=1&bar=2"
+	params := url.Values{
+		"CallSid": {"CA1234567890ABCDE"},
+		"Caller":  {"+12349013030"},
+		"Digits":  {"1234"},
+		"From":    {"+12349013030"},
+		"To":      {"+18005551212"},
+	}
+
+	It("computes the Twilio signature", func() {
+		Expect(Signature("12345", rawURL, params)).To(Equal("0/KCTR6DLpKmkAf8muzZqo1nDgQ="))
+	})
+
+	It("verifies signed requests", func() {
+		r, _ := http.NewRequest("POST", rawURL, strings.NewReader(params.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.Header.Set(SignatureHeader, "0/KCTR6DLpKmkAf8muzZqo1nDgQ=")
+		Expect(VerifySignature("12345", rawURL, r)).To(Succeed())
+		Expect(VerifySignature("54321", rawURL, r)).To(Equal(ErrInvalidSignature))
+	})
+})
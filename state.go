@@ -0,0 +1,266 @@
+package twiml
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultStateParam is the query parameter holding state added to callback URLs
+const DefaultStateParam = "state"
+
+// MinStateKeyLength is the shortest key accepted by NewStateCodec
+const MinStateKeyLength = 16
+
+// DefaultStateMaxAge is the age after which state is rejected unless the codec sets its own
+// maximum.  It is the longest Twilio lets a call run.
+const DefaultStateMaxAge = 4 * time.Hour
+
+var (
+	// ErrInvalidState is returned when state has been altered or was signed with another key
+	ErrInvalidState = errors.New("Invalid callback state")
+	// ErrStateExpired is returned when state is older than the maximum age of the codec
+	ErrStateExpired = errors.New("Callback state has expired")
+	// ErrMissingState is returned when a callback request has no state
+	ErrMissingState = errors.New("Callback request has no state")
+)
+
+// StateCodec carries state across callbacks in a query parameter of the action and callback
+// URLs of a response, so multi-step flows do not need server side storage.  State is encoded
+// as JSON and signed so it can not be altered by the caller.  It is readable unless the codec
+// encrypts it.
+type StateCodec struct {
+	signKey    []byte
+	encryptKey []byte
+	encrypt    bool
+	param      string
+	maxAge     time.Duration
+	now        func() time.Time
+}
+
+// StateOption configures a StateCodec
+type StateOption func(*StateCodec)
+
+// EncryptState encrypts state with AES-GCM so it can not be read from the URL
+func EncryptState() StateOption {
+	return func(c *StateCodec) {
+		c.encrypt = true
+	}
+}
+
+// StateParam sets the name of the query parameter holding the state
+func StateParam(name string) StateOption {
+	return func(c *StateCodec) {
+		c.param = name
+	}
+}
+
+// StateMaxAge rejects state older than d when it is decoded instead of DefaultStateMaxAge.
+// State never expires when d is zero.
+func StateMaxAge(d time.Duration) StateOption {
+	return func(c *StateCodec) {
+		c.maxAge = d
+	}
+}
+
+// NewStateCodec returns a codec that signs state with key, which must be at least
+// MinStateKeyLength bytes and kept secret.  State expires after DefaultStateMaxAge.
+func NewStateCodec(key []byte, opts ...StateOption) (*StateCodec, error) {
+	if len(key) < MinStateKeyLength {
+		return nil, fmt.Errorf("State key must be at least %d bytes", MinStateKeyLength)
+	}
+	c := &StateCodec{
+		signKey:    deriveKey(key, "sign"),
+		encryptKey: deriveKey(key, "encrypt"),
+		param:      DefaultStateParam,
+		maxAge:     DefaultStateMaxAge,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// deriveKey derives separate keys for signing and encryption from the key of the codec
+func deriveKey(key []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("twiml state " + purpose))
+	return mac.Sum(nil)
+}
+
+// envelope is the signed content of a state parameter
+type envelope struct {
+	Issued int64           `json:"iat"`
+	State  json.RawMessage `json:"s"`
+}
+
+// Encode returns state as a signed token for use in a URL
+func (c *StateCodec) Encode(state interface{}) (string, error) {
+	s, err := json.Marshal(state)
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(envelope{Issued: c.now().Unix(), State: s})
+	if err != nil {
+		return "", err
+	}
+	if c.encrypt {
+		if payload, err = c.seal(payload); err != nil {
+			return "", err
+		}
+	}
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(c.sign(payload)), nil
+}
+
+// Decode verifies a token returned by Encode and decodes the state into state
+func (c *StateCodec) Decode(token string, state interface{}) error {
+	enc := base64.RawURLEncoding
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return ErrInvalidState
+	}
+	payload, err := enc.DecodeString(parts[0])
+	if err != nil {
+		return ErrInvalidState
+	}
+	sig, err := enc.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(sig, c.sign(payload)) {
+		return ErrInvalidState
+	}
+	if c.encrypt {
+		if payload, err = c.open(payload); err != nil {
+			return ErrInvalidState
+		}
+	}
+	var env envelope
+	if err := json.Unmarshal(payload, &env); err != nil {
+		return ErrInvalidState
+	}
+	if c.maxAge > 0 && c.now().Sub(time.Unix(env.Issued, 0)) > c.maxAge {
+		return ErrStateExpired
+	}
+	return json.Unmarshal(env.State, state)
+}
+
+func (c *StateCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.signKey)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// seal encrypts the payload, prefixing it with a random nonce
+func (c *StateCodec) seal(payload []byte) ([]byte, error) {
+	aead, err := c.aead()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, payload, nil), nil
+}
+
+func (c *StateCodec) open(sealed []byte) ([]byte, error) {
+	aead, err := c.aead()
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrInvalidState
+	}
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+}
+
+func (c *StateCodec) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(c.encryptKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// AddToURL returns rawURL with the encoded state in its query, replacing any state already
+// there.  Relative URLs are allowed.
+func (c *StateCodec) AddToURL(rawURL string, state interface{}) (string, error) {
+	token, err := c.Encode(state)
+	if err != nil {
+		return "", err
+	}
+	return c.addToken(rawURL, token)
+}
+
+func (c *StateCodec) addToken(rawURL string, token string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set(c.param, token)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// Attach adds the encoded state to every action and callback URL in the markup, including
+// the URL of a Redirect.  The media URLs of Play and Media are unchanged.  Verbs without
+// a URL, such as a Gather without an action, are unchanged, so set the URLs before calling
+// Attach.
+func (c *StateCodec) Attach(m Markup, state interface{}) error {
+	token, err := c.Encode(state)
+	if err != nil {
+		return err
+	}
+	return Walk(m, func(path string, m Markup) error {
+		switch m.(type) {
+		case *Play, *Media:
+			return nil
+		}
+		h, ok := m.(urlHolder)
+		if !ok {
+			return nil
+		}
+		for _, f := range h.urls() {
+			if *f == "" {
+				continue
+			}
+			u, err := c.addToken(*f, token)
+			if err != nil {
+				return fmt.Errorf("%s: %s", path, err)
+			}
+			*f = u
+		}
+		return nil
+	})
+}
+
+// State verifies and decodes the state in the query of a callback request.  It returns
+// ErrMissingState when the request has no state.
+func (c *StateCodec) State(r *http.Request, state interface{}) error {
+	token := r.URL.Query().Get(c.param)
+	if token == "" {
+		return ErrMissingState
+	}
+	return c.Decode(token, state)
+}
+
+// Bind binds the callback parameters of the request into cbRequest with DefaultBinder, then
+// verifies and decodes the state in its URL into state
+func (c *StateCodec) Bind(cbRequest interface{}, state interface{}, r *http.Request) error {
+	if err := Bind(cbRequest, r); err != nil {
+		return err
+	}
+	return c.State(r, state)
+}
@@ -0,0 +1,163 @@
+package twiml
+
+import (
+	"encoding/base64"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type menuState struct {
+	Menu     string `json:"menu"`
+	Customer int    `json:"customer"`
+}
+
+var _ = Describe("Callback state", func() {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	state := menuState{Menu: "billing", Customer: 42}
+
+	codec := func(opts ...StateOption) *StateCodec {
+		c, err := NewStateCodec(key, opts...)
+		Expect(err).ToNot(HaveOccurred())
+		return c
+	}
+
+	It("requires a long enough key", func() {
+		_, err := NewStateCodec([]byte("short"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("round trips state", func() {
+		for _, c := range []*StateCodec{codec(), codec(EncryptState())} {
+			token, err := c.Encode(state)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(url.QueryEscape(token)).To(Equal(token))
+			var got menuState
+			Expect(c.Decode(token, &got)).To(Succeed())
+			Expect(got).To(Equal(state))
+		}
+	})
+
+	It("rejects altered state", func() {
+		c := codec()
+		token, err := c.Encode(state)
+		Expect(err).ToNot(HaveOccurred())
+		parts := strings.Split(token, ".")
+		payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+		Expect(err).ToNot(HaveOccurred())
+		altered := strings.Replace(string(payload), "42", "43", 1)
+		forged := base64.RawURLEncoding.EncodeToString([]byte(altered)) + "." + parts[1]
+
+		var got menuState
+		Expect(c.Decode(forged, &got)).To(Equal(ErrInvalidState))
+		Expect(c.Decode(token+"x", &got)).To(Equal(ErrInvalidState))
+		Expect(c.Decode("garbage", &got)).To(Equal(ErrInvalidState))
+
+		other, err := NewStateCodec([]byte("another key of 32 bytes.........."))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(other.Decode(token, &got)).To(Equal(ErrInvalidState))
+	})
+
+	It("hides encrypted state", func() {
+		token, err := codec(EncryptState()).Encode(state)
+		Expect(err).ToNot(HaveOccurred())
+		payload, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(payload)).ToNot(ContainSubstring("billing"))
+
+		var got menuState
+		Expect(codec().Decode(token, &got)).To(Equal(ErrInvalidState))
+	})
+
+	It("expires old state", func() {
+		c := codec(StateMaxAge(time.Hour))
+		issued := time.Now()
+		c.now = func() time.Time { return issued }
+		token, err := c.Encode(state)
+		Expect(err).ToNot(HaveOccurred())
+
+		var got menuState
+		c.now = func() time.Time { return issued.Add(59 * time.Minute) }
+		Expect(c.Decode(token, &got)).To(Succeed())
+		c.now = func() time.Time { return issued.Add(61 * time.Minute) }
+		Expect(c.Decode(token, &got)).To(Equal(ErrStateExpired))
+	})
+
+	It("expires state after the default age", func() {
+		c := codec()
+		issued := time.Now()
+		c.now = func() time.Time { return issued }
+		token, err := c.Encode(state)
+		Expect(err).ToNot(HaveOccurred())
+
+		var got menuState
+		c.now = func() time.Time { return issued.Add(DefaultStateMaxAge + time.Minute) }
+		Expect(c.Decode(token, &got)).To(Equal(ErrStateExpired))
+
+		c = codec(StateMaxAge(0))
+		c.now = func() time.Time { return issued.Add(DefaultStateMaxAge + time.Minute) }
+		Expect(c.Decode(token, &got)).To(Succeed())
+	})
+
+	It("adds state to URLs", func() {
+		c := codec(StateParam("s"))
+		u, err := c.AddToURL("/menu?step=2", state)
+		Expect(err).ToNot(HaveOccurred())
+		parsed, err := url.Parse(u)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed.Path).To(Equal("/menu"))
+		Expect(parsed.Query().Get("step")).To(Equal("2"))
+
+		var got menuState
+		Expect(c.Decode(parsed.Query().Get("s"), &got)).To(Succeed())
+		Expect(got).To(Equal(state))
+	})
+
+	It("attaches state to every callback URL in a response", func() {
+		r := NewResponse()
+		g := &Gather{Action: "menu", NumDigits: 1}
+		g.Add(&Play{URL: "welcome.mp3"})
+		d := &Dial{}
+		d.Add(&Number{Number: "+15555555555", URL: "whisper"})
+		r.Add(g, d, &Record{}, &Redirect{URL: "https://example.com/menu?retry=1"})
+
+		c := codec()
+		Expect(c.Attach(r, state)).To(Succeed())
+		Expect(g.Action).To(HavePrefix("menu?state="))
+		Expect(g.Children[0].(*Play).URL).To(Equal("welcome.mp3"))
+		Expect(d.Action).To(BeEmpty())
+		Expect(d.Children[0].(*Number).URL).To(HavePrefix("whisper?state="))
+		Expect(r.Children[2].(*Record).Action).To(BeEmpty())
+		Expect(r.Children[3].(*Redirect).URL).To(HavePrefix("https://example.com/menu?retry=1&state="))
+		Expect(r.Validate()).To(Succeed())
+	})
+
+	It("binds a callback and its state", func() {
+		c := codec(EncryptState())
+		action, err := c.AddToURL("https://example.com/menu", state)
+		Expect(err).ToNot(HaveOccurred())
+
+		form := url.Values{"CallSid": {"CA123"}, "Digits": {"2"}}
+		req := httptest.NewRequest("POST", action, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		var cb GatherActionRequest
+		var got menuState
+		Expect(c.Bind(&cb, &got, req)).To(Succeed())
+		Expect(cb.Digits).To(Equal("2"))
+		Expect(got).To(Equal(state))
+
+		get := httptest.NewRequest("GET", action+"&"+form.Encode(), nil)
+		cb, got = GatherActionRequest{}, menuState{}
+		Expect(c.Bind(&cb, &got, get)).To(Succeed())
+		Expect(cb.CallSid).To(Equal("CA123"))
+		Expect(got).To(Equal(state))
+
+		missing := httptest.NewRequest("GET", "https://example.com/menu", nil)
+		Expect(c.State(missing, &got)).To(Equal(ErrMissingState))
+	})
+})
@@ -0,0 +1,174 @@
+package twiml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Template is a TwiML response with text/template actions in its text and attribute values.
+// The response is parsed once and rendered for each call with different data.  A Template is
+// safe for concurrent use.
+type Template struct {
+	name  string
+	root  *node
+	tmpls map[string]*template.Template
+}
+
+// ParseTemplate parses a template from TwiML, or from YAML when src does not start with an XML
+// element.  The YAML schema describes each verb with its attributes, text and nested verbs:
+//
+//	verb: Response
+//	children:
+//	- verb: Say
+//	  attrs: {voice: alice}
+//	  text: Hello {{.Name}}
+//
+// Template actions are compiled with missingkey=error, so rendering fails on missing data.
+// Values are XML escaped when the response is encoded but not URL escaped.  Insert values into
+// URLs with the query function, which encodes pairs of names and values as a query string, or
+// the builtin urlquery:
+//
+//	<Gather action='{{.Base}}/pay?{{query "amount" .Amount "ref" .Ref}}'/>
+func ParseTemplate(name string, src []byte) (*Template, error) {
+	var root *node
+	var err error
+	if trimmed := bytes.TrimSpace(src); len(trimmed) > 0 && trimmed[0] == '<' {
+		root, err = parseXMLNode(src)
+	} else {
+		root = new(node)
+		err = yaml.Unmarshal(src, root)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %s", name, err)
+	}
+	if root.Name != "Response" {
+		return nil, fmt.Errorf("template %s: expected Response, found '%s'", name, root.Name)
+	}
+
+	t := &Template{name: name, root: root, tmpls: make(map[string]*template.Template)}
+	if err := t.compile(root); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// parseXMLNode reads the first element of an XML document into a node
+func parseXMLNode(src []byte) (*node, error) {
+	d := xml.NewDecoder(bytes.NewReader(src))
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no TwiML element found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return readNode(d, start, &lineIndex{data: src})
+		}
+	}
+}
+
+// templateFuncs are the functions available to templates in addition to the builtins
+var templateFuncs = template.FuncMap{
+	"query": query,
+}
+
+// query encodes pairs of names and values as a URL query string, in the order given
+func query(pairs ...interface{}) (string, error) {
+	if len(pairs)%2 != 0 {
+		return "", fmt.Errorf("query needs pairs of names and values, got %d arguments", len(pairs))
+	}
+	var b strings.Builder
+	for i := 0; i < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(url.QueryEscape(fmt.Sprint(pairs[i])))
+		b.WriteByte('=')
+		b.WriteString(url.QueryEscape(fmt.Sprint(pairs[i+1])))
+	}
+	return b.String(), nil
+}
+
+// compile parses every string in the tree that contains a template action
+func (t *Template) compile(n *node) error {
+	strs := []string{n.Text}
+	for _, v := range n.Attrs {
+		strs = append(strs, v)
+	}
+	for _, s := range strs {
+		if _, ok := t.tmpls[s]; ok || !strings.Contains(s, "{{") {
+			continue
+		}
+		tmpl, err := template.New(t.name).Option("missingkey=error").Funcs(templateFuncs).Parse(s)
+		if err != nil {
+			return err
+		}
+		t.tmpls[s] = tmpl
+	}
+	for _, child := range n.Children {
+		if err := t.compile(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Render executes the template with the data provided and returns the validated response.
+// Values are substituted before encoding, so they are escaped when the response is encoded.
+func (t *Template) Render(data interface{}) (*Response, error) {
+	n, err := t.render(t.root, data)
+	if err != nil {
+		return nil, err
+	}
+	r := NewResponse()
+	if err := setMarkup(r, n); err != nil {
+		return nil, fmt.Errorf("template %s: %s", t.name, err)
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// render copies the tree, executing the template of each string
+func (t *Template) render(n *node, data interface{}) (*node, error) {
+	out := &node{Name: n.Name, Line: n.Line, Attrs: make(map[string]string, len(n.Attrs))}
+	var err error
+	if out.Text, err = t.execute(n.Text, data); err != nil {
+		return nil, err
+	}
+	for k, v := range n.Attrs {
+		if out.Attrs[k], err = t.execute(v, data); err != nil {
+			return nil, err
+		}
+	}
+	for _, child := range n.Children {
+		c, err := t.render(child, data)
+		if err != nil {
+			return nil, err
+		}
+		out.Children = append(out.Children, c)
+	}
+	return out, nil
+}
+
+func (t *Template) execute(s string, data interface{}) (string, error) {
+	tmpl, ok := t.tmpls[s]
+	if !ok {
+		return s, nil
+	}
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,100 @@
+package twiml
+
+import (
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TwiML templates", func() {
+	data := map[string]interface{}{
+		"Name":    "Tom & Jerry <3",
+		"Amount":  "$12.50",
+		"Timeout": 20,
+		"Base":    "https://example.com",
+	}
+
+	It("renders a TwiML template", func() {
+		t, err := ParseTemplate("balance", []byte(`<Response>
+  <Say voice="alice">Hello {{.Name}}, you owe {{.Amount}}</Say>
+  <Gather action="{{.Base}}/pay?amount={{.Amount}}" timeout="{{.Timeout}}" numDigits="1"></Gather>
+</Response>`))
+		Expect(err).ToNot(HaveOccurred())
+
+		r, err := t.Render(data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(r.Children).To(Equal([]Markup{
+			&Say{Voice: "alice", Text: "Hello Tom & Jerry <3, you owe $12.50"},
+			&Gather{Action: "https://example.com/pay?amount=$12.50", Timeout: 20, NumDigits: 1},
+		}))
+
+		s, err := r.String()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(s).To(ContainSubstring("Hello Tom &amp; Jerry &lt;3, you owe $12.50"))
+	})
+
+	It("escapes values inserted into URLs", func() {
+		t, err := ParseTemplate("pay", []byte(`<Response>
+  <Gather action='{{.Base}}/pay?{{query "name" .Name "amount" .Amount}}' numDigits="1"></Gather>
+  <Redirect>{{.Base}}/next?name={{urlquery .Name}}</Redirect>
+</Response>`))
+		Expect(err).ToNot(HaveOccurred())
+
+		r, err := t.Render(data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(r.Children[0].(*Gather).Action).To(Equal("https://example.com/pay?name=Tom+%26+Jerry+%3C3&amount=%2412.50"))
+		Expect(r.Children[1].(*Redirect).URL).To(Equal("https://example.com/next?name=Tom+%26+Jerry+%3C3"))
+
+		u, err := url.Parse(r.Children[0].(*Gather).Action)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(u.Query().Get("name")).To(Equal("Tom & Jerry <3"))
+
+		t, err = ParseTemplate("t", []byte(`<Response><Redirect>/next?{{query "name"}}</Redirect></Response>`))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = t.Render(data)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("renders a YAML template", func() {
+		t, err := ParseTemplate("balance", []byte(`
+verb: Response
+children:
+- verb: Say
+  text: Hello {{.Name}}
+- verb: Dial
+  attrs:
+    timeout: 15
+  children:
+  - verb: Number
+    text: "+15555555555"
+`))
+		Expect(err).ToNot(HaveOccurred())
+
+		r, err := t.Render(data)
+		Expect(err).ToNot(HaveOccurred())
+		d := &Dial{Timeout: 15}
+		d.Add(&Number{Number: "+15555555555"})
+		Expect(r.Children).To(Equal([]Markup{&Say{Text: "Hello Tom & Jerry <3"}, d}))
+	})
+
+	It("reports missing data, bad values and invalid output", func() {
+		t, err := ParseTemplate("t", []byte(`<Response><Say>{{.Missing}}</Say></Response>`))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = t.Render(data)
+		Expect(err).To(HaveOccurred())
+
+		t, err = ParseTemplate("t", []byte(`<Response><Pause length="{{.Name}}"></Pause></Response>`))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = t.Render(data)
+		Expect(err).To(HaveOccurred())
+
+		t, err = ParseTemplate("t", []byte(`<Response><Say voice="{{.Name}}">Hi</Say></Response>`))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = t.Render(data)
+		Expect(err).To(BeAssignableToTypeOf(ValidationError{}))
+
+		_, err = ParseTemplate("t", []byte(`<Response><Say>{{.Name</Say></Response>`))
+		Expect(err).To(HaveOccurred())
+	})
+})
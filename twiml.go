@@ -2,10 +2,13 @@
 // services with instructions for twilio how to handle incoming call or message.
 package twiml
 
+//go:generate go run ./internal/twimlgen
+
 import (
 	"bytes"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"strings"
 )
 
@@ -17,6 +20,15 @@ type Markup interface {
 	Validate() error
 }
 
+// Container is satisfied by verbs that nest other markup, such as Response, Dial, Gather and Client
+type Container interface {
+	Markup
+	// Kids returns the markup nested in the verb
+	Kids() []Markup
+	// SetKids replaces the markup nested in the verb
+	SetKids(ml []Markup)
+}
+
 // ValidationError will return one or more errors encountered during validation
 type ValidationError struct {
 	Errors []error
@@ -33,9 +45,19 @@ func (v ValidationError) Error() string {
 
 // Response container for other TwiML verbs
 type Response struct {
-	XMLName                xml.Name `xml:"Response"`
-	IgnoreValidationErrors bool     `xml:"-"`
-	Children               []Markup
+	XMLName xml.Name `xml:"Response"`
+	// IgnoreValidationErrors encodes the response when it fails validation, as in
+	// ValidateWarn mode
+	IgnoreValidationErrors bool `xml:"-"`
+	// Validation controls whether validation errors stop the response from being encoded
+	Validation ValidationMode `xml:"-"`
+	// Limits overrides DefaultLimits for the response
+	Limits   *Limits `xml:"-"`
+	Children []Markup
+
+	// decoded is the document read by UnmarshalXML, kept for lint rules that check attribute
+	// values lost when decoding into verbs
+	decoded *node
 }
 
 // Type returns the XML name of the verb
@@ -44,25 +66,33 @@ func (r *Response) Type() string {
 }
 
 // Validate recursively validates all nested verbs within the response, returning a ValidationError
-// if any are constructed improperly
+// if any are constructed improperly or over its Limits.  The MaxBytes limit is only checked when
+// the response is encoded, so validating does not encode the response a second time.
 func (r *Response) Validate() error {
+	return r.validate()
+}
+
+// validate checks everything except the size of the encoded response, which is checked as
+// the response is written
+func (r *Response) validate() error {
 	if len(r.Children) == 0 {
 		return ValidationError{[]error{fmt.Errorf("Can not encode an empty response")}}
 	}
 	var errs []error
 	for _, s := range r.Children {
-		switch t := s.Type(); t {
-		case "Enqueue", "Hangup", "Leave", "Pause", "Play", "Record", "Redirect", "Reject", "Say", "Dial", "Gather", "Sms":
-			if childErr := s.Validate(); childErr != nil {
-				errs = append(errs, childErr)
-			}
-		default:
+		if !responseVerbs[s.Type()] {
 			return ValidationError{[]error{fmt.Errorf("Unknown markup type %T as child of Response", s)}}
 		}
+		if childErr := s.Validate(); childErr != nil {
+			errs = append(errs, childErr)
+		}
 	}
 	if len(errs) > 0 {
 		return ValidationError{errs}
 	}
+	if errs := checkLimits(r, r.limits()); len(errs) > 0 {
+		return ValidationError{errs}
+	}
 	return nil
 }
 
@@ -81,31 +111,67 @@ func (r *Response) Add(ml ...Markup) {
 	return
 }
 
-// Encode returns an XML encoded response or a ValidationError if any
-// markup fails validation.
-func (r *Response) Encode() ([]byte, error) {
-	var buf = new(bytes.Buffer)
+// Kids returns the verbs in the response
+func (r *Response) Kids() []Markup {
+	return r.Children
+}
 
-	if err := r.Validate(); err != nil {
-		return buf.Bytes(), err
-	}
+// SetKids replaces the verbs in the response
+func (r *Response) SetKids(ml []Markup) {
+	r.Children = ml
+}
 
-	enc := xml.NewEncoder(buf)
-	enc.Indent("", "  ")
+// EncodeOptions control how EncodeTo writes a response
+type EncodeOptions struct {
+	// Indent is written once for each level of nesting before each verb
+	Indent string
+	// Compact writes the response without newlines or indentation, ignoring Indent
+	Compact bool
+	// OmitHeader skips the XML declaration
+	OmitHeader bool
+}
 
-	_, err := buf.Write([]byte(xml.Header))
-	if err != nil {
-		return buf.Bytes(), err
-	}
+// DefaultEncodeOptions are the options used by Encode, String and WriteTo
+var DefaultEncodeOptions = EncodeOptions{Indent: "  "}
 
-	if err := enc.Encode(r); err != nil {
-		return buf.Bytes(), err
-	}
-	return buf.Bytes(), nil
+// EncodeTo validates the response and writes it to w as XML.  The response is encoded into a
+// pooled buffer and written with a single call, so nothing is written when it fails
+// validation.
+func (r *Response) EncodeTo(w io.Writer, opts EncodeOptions) error {
+	return r.encodeTo(w, opts)
+}
+
+// WriteTo validates the response and writes it to w as XML, returning the number of bytes
+// written.  It can be used to write a response directly to an http.ResponseWriter.
+func (r *Response) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	err := r.EncodeTo(cw, DefaultEncodeOptions)
+	return cw.n, err
+}
+
+// countingWriter counts the bytes written to w
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	return n, err
+}
+
+// Encode returns an XML encoded response or a ValidationError if any
+// markup fails validation in ValidateStrict mode.
+func (r *Response) Encode() ([]byte, error) {
+	var buf = new(bytes.Buffer)
+	err := r.MarshalTwiML(buf)
+	return buf.Bytes(), err
 }
 
 // String returns a formatted XML response
 func (r *Response) String() (string, error) {
-	b, err := r.Encode()
-	return string(b), err
+	var sb strings.Builder
+	err := r.EncodeTo(&sb, DefaultEncodeOptions)
+	return sb.String(), err
 }
@@ -1,6 +1,7 @@
 package twiml
 
 import (
+	"bytes"
 	"encoding/xml"
 	"strings"
 	"testing"
@@ -75,4 +76,40 @@ var _ = Describe("TwiML responses", func() {
 		err := d.Validate()
 		Expect(err).To(HaveOccurred())
 	})
+
+	It("can stream a response to a writer", func() {
+		r := NewResponse()
+		r.Add(&Say{Text: "Hello"})
+		exp := buildResponse(x("<Say>Hello</Say>", 2))
+
+		var buf bytes.Buffer
+		n, err := r.WriteTo(&buf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buf.String()).To(Equal(exp))
+		Expect(n).To(Equal(int64(len(exp))))
+	})
+
+	It("can encode with options", func() {
+		r := NewResponse()
+		g := &Gather{NumDigits: 1}
+		g.Add(&Say{Text: "Press 1"})
+		r.Add(g)
+
+		var buf bytes.Buffer
+		err := r.EncodeTo(&buf, EncodeOptions{Compact: true, OmitHeader: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buf.String()).To(Equal(`<Response><Gather numDigits="1"><Say>Press 1</Say></Gather></Response>`))
+
+		buf.Reset()
+		err = r.EncodeTo(&buf, EncodeOptions{Indent: "\t"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buf.String()).To(Equal(xml.Header + "<Response>\n\t<Gather numDigits=\"1\">\n\t\t<Say>Press 1</Say>\n\t</Gather>\n</Response>"))
+	})
+
+	It("writes nothing when validation fails", func() {
+		var buf bytes.Buffer
+		_, err := NewResponse().WriteTo(&buf)
+		Expect(err).To(HaveOccurred())
+		Expect(buf.Len()).To(Equal(0))
+	})
 })
@@ -0,0 +1,172 @@
+package twimltest
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/BTBurke/twiml"
+	"github.com/gorilla/schema"
+)
+
+var encoder = schema.NewEncoder()
+
+// Default parameters of simulated calls
+const (
+	AccountSid  = "AC00000000000000000000000000000000"
+	APIVersion  = "2010-04-01"
+	DefaultFrom = "+15005550006"
+	DefaultTo   = "+15005550001"
+)
+
+var sids uint64
+
+// newSid returns a unique sid with the prefix provided
+func newSid(prefix string) string {
+	return fmt.Sprintf("%s%032x", prefix, atomic.AddUint64(&sids, 1))
+}
+
+// Call returns the voice parameters shared by every callback of the request's call
+func (r *Request) Call() twiml.VoiceRequest {
+	var vr twiml.VoiceRequest
+	if err := twiml.Bind(&vr, r.HTTPRequest()); err != nil {
+		panic(fmt.Sprintf("twimltest: can not bind call parameters: %s", err))
+	}
+	return vr
+}
+
+// follow creates a callback request for the same call, sent to the same URL
+func (r *Request) follow(cbRequest interface{}) *Request {
+	next := NewRequest(cbRequest)
+	next.URL = r.URL
+	next.AuthToken = r.AuthToken
+	return next
+}
+
+// IncomingCall simulates the initial request for a new inbound call
+func IncomingCall(from string, to string) *Request {
+	return NewRequest(twiml.VoiceRequest{
+		CallSid:    newSid("CA"),
+		AccountSid: AccountSid,
+		From:       from,
+		To:         to,
+		CallStatus: twiml.Ringing,
+		APIVersion: APIVersion,
+		Direction:  twiml.Inbound,
+		Called:     to,
+		Caller:     from,
+	})
+}
+
+// inProgress returns the call parameters of the request with the status set to in-progress
+func (r *Request) inProgress() twiml.VoiceRequest {
+	vr := r.Call()
+	vr.CallStatus = twiml.InProgress
+	return vr
+}
+
+// DialAction simulates the action callback of a Dial verb for the same call
+func (r *Request) DialAction(status string) *Request {
+	cb := twiml.DialActionRequest{
+		VoiceRequest:   r.inProgress(),
+		DialCallStatus: status,
+		DialCallSid:    newSid("CA"),
+	}
+	if status == twiml.Completed {
+		cb.DialBridged = true
+	}
+	return r.follow(cb)
+}
+
+// GatherAction simulates the action callback of a Gather verb for the same call with
+// the digits entered by the caller
+func (r *Request) GatherAction(digits string) *Request {
+	return r.follow(twiml.GatherActionRequest{
+		VoiceRequest: r.inProgress(),
+		Digits:       digits,
+	})
+}
+
+// SpeechAction simulates the action callback of a Gather verb for the same call with
+// the speech recognized from the caller
+func (r *Request) SpeechAction(speech string, confidence float64) *Request {
+	return r.follow(twiml.GatherActionRequest{
+		VoiceRequest: r.inProgress(),
+		SpeechResult: speech,
+		Confidence:   confidence,
+	})
+}
+
+// RecordAction simulates the action callback of a Record verb for the same call
+func (r *Request) RecordAction(recordingURL string, duration int) *Request {
+	return r.follow(twiml.RecordActionRequest{
+		VoiceRequest:      r.inProgress(),
+		RecordingSid:      newSid("RE"),
+		RecordingURL:      recordingURL,
+		RecordingDuration: duration,
+	})
+}
+
+// RecordingComplete simulates the recording status callback of a Record verb for the same call
+func (r *Request) RecordingComplete(recordingURL string, duration int) *Request {
+	vr := r.Call()
+	return r.follow(twiml.RecordingStatusCallbackRequest{
+		AccountSid:        vr.AccountSid,
+		CallSid:           vr.CallSid,
+		RecordingSid:      newSid("RE"),
+		RecordingURL:      recordingURL,
+		RecordingStatus:   "completed",
+		RecordingDuration: duration,
+		RecordingChannels: 1,
+		RecordingSource:   "RecordVerb",
+	})
+}
+
+// Transcription simulates the transcribe callback of a Record verb for the same call
+func (r *Request) Transcription(text string) *Request {
+	vr := r.Call()
+	return r.follow(twiml.TranscribeCallbackRequest{
+		TranscriptionSid:    newSid("TR"),
+		TranscriptionText:   text,
+		TranscriptionStatus: "completed",
+		RecordingSid:        newSid("RE"),
+		CallSid:             vr.CallSid,
+		AccountSid:          vr.AccountSid,
+		From:                vr.From,
+		To:                  vr.To,
+		CallStatus:          twiml.Completed,
+		APIVersion:          vr.APIVersion,
+		Direction:           vr.Direction,
+	})
+}
+
+// Hangup simulates the status callback sent when the call ends
+func (r *Request) Hangup() *Request {
+	vr := r.Call()
+	vr.CallStatus = twiml.Completed
+	return r.follow(vr)
+}
+
+// DialAction simulates the action callback of a Dial verb for a new call
+func DialAction(status string) *Request {
+	return IncomingCall(DefaultFrom, DefaultTo).DialAction(status)
+}
+
+// GatherAction simulates the action callback of a Gather verb for a new call
+func GatherAction(digits string) *Request {
+	return IncomingCall(DefaultFrom, DefaultTo).GatherAction(digits)
+}
+
+// RecordAction simulates the action callback of a Record verb for a new call
+func RecordAction(recordingURL string, duration int) *Request {
+	return IncomingCall(DefaultFrom, DefaultTo).RecordAction(recordingURL, duration)
+}
+
+// RecordingComplete simulates the recording status callback of a Record verb for a new call
+func RecordingComplete(recordingURL string, duration int) *Request {
+	return IncomingCall(DefaultFrom, DefaultTo).RecordingComplete(recordingURL, duration)
+}
+
+// Transcription simulates the transcribe callback of a Record verb for a new call
+func Transcription(text string) *Request {
+	return IncomingCall(DefaultFrom, DefaultTo).Transcription(text)
+}
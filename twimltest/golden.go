@@ -0,0 +1,114 @@
+package twimltest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BTBurke/twiml"
+)
+
+// update is namespaced so it does not collide with an -update flag of the tests using the package
+var update = flag.Bool("twimltest.update", false, "update twimltest golden files")
+
+// GoldenDir is the directory golden files are read from and written to
+var GoldenDir = "testdata"
+
+// TestingT is the subset of testing.T used by assertions
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Canonicalize decodes TwiML and encodes it again with Response.Encode.  Documents that only
+// differ in attribute order, indentation or whitespace have the same canonical form.  The
+// document is not validated, so values Twilio added after this package can be compared.
+func Canonicalize(b []byte) ([]byte, error) {
+	res, err := twiml.Decode(b)
+	if err != nil {
+		return nil, err
+	}
+	return encode(res)
+}
+
+// encode writes a response without validating it
+func encode(res *twiml.Response) ([]byte, error) {
+	c := *res
+	c.Validation = twiml.ValidateOff
+	return c.Encode()
+}
+
+// canonical returns the canonical TwiML of a string, byte slice or *twiml.Response
+func canonical(v interface{}) ([]byte, error) {
+	switch t := v.(type) {
+	case *twiml.Response:
+		return encode(t)
+	case []byte:
+		return Canonicalize(t)
+	case string:
+		return Canonicalize([]byte(t))
+	default:
+		return nil, fmt.Errorf("twimltest: can not compare TwiML of type %T", v)
+	}
+}
+
+// AssertTwiMLEqual reports an error listing the differences found by twiml.Diff when two
+// TwiML documents are not equal after canonicalization.  Each document may be a string, byte slice
+// or *twiml.Response.
+func AssertTwiMLEqual(t TestingT, want interface{}, got interface{}) bool {
+	t.Helper()
+	w, err := canonical(want)
+	if err != nil {
+		t.Errorf("twimltest: invalid expected TwiML: %s", err)
+		return false
+	}
+	g, err := canonical(got)
+	if err != nil {
+		t.Errorf("twimltest: invalid TwiML: %s", err)
+		return false
+	}
+	if bytes.Equal(w, g) {
+		return true
+	}
+	var changes []string
+	wr, _ := twiml.Decode(w)
+	gr, _ := twiml.Decode(g)
+	for _, c := range twiml.Diff(wr, gr) {
+		changes = append(changes, c.String())
+	}
+	t.Errorf("TwiML is not equal:\n%s\n\nexpected:\n%s\n\nactual:\n%s", strings.Join(changes, "\n"), w, g)
+	return false
+}
+
+// AssertGolden compares TwiML against the golden file GoldenDir/name.xml.  Run tests with
+// -twimltest.update to write the canonical form of got to the golden file instead.
+func AssertGolden(t TestingT, name string, got interface{}) bool {
+	t.Helper()
+	path := filepath.Join(GoldenDir, name+".xml")
+	if *update {
+		g, err := canonical(got)
+		if err != nil {
+			t.Errorf("twimltest: invalid TwiML: %s", err)
+			return false
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Errorf("twimltest: can not create golden directory: %s", err)
+			return false
+		}
+		if err := ioutil.WriteFile(path, g, 0644); err != nil {
+			t.Errorf("twimltest: can not update golden file: %s", err)
+			return false
+		}
+		return true
+	}
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Errorf("twimltest: can not read golden file, run with -twimltest.update to create it: %s", err)
+		return false
+	}
+	return AssertTwiMLEqual(t, want, got)
+}
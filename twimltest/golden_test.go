@@ -0,0 +1,73 @@
+package twimltest
+
+import (
+	"flag"
+	"fmt"
+	"testing"
+
+	"github.com/BTBurke/twiml"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeT struct {
+	errors []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func greeting() *twiml.Response {
+	res := twiml.NewResponse()
+	g := &twiml.Gather{Action: "/menu", NumDigits: 1, Timeout: 5}
+	g.Add(&twiml.Say{Voice: twiml.Alice, Text: "Press 1 for sales"})
+	res.Add(g, &twiml.Hangup{})
+	return res
+}
+
+func TestCanonicalizeIgnoresFormatting(t *testing.T) {
+	doc := `<Response><Gather timeout="5"   numDigits="1" action="/menu">
+	<Say voice="alice">
+		Press 1 for sales
+	</Say></Gather>
+
+	<Hangup/></Response>`
+	assert.True(t, AssertTwiMLEqual(t, greeting(), doc))
+}
+
+func TestCanonicalizeSkipsValidation(t *testing.T) {
+	doc := `<Response><Say voice="Polly.Joanna">Hello</Say></Response>`
+	_, err := twiml.Decode([]byte(doc))
+	assert.NoError(t, err)
+
+	b, err := Canonicalize([]byte(doc))
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `<Say voice="Polly.Joanna">Hello</Say>`)
+
+	res := twiml.NewResponse()
+	res.Add(&twiml.Say{Voice: "Polly.Joanna", Text: "Hello"})
+	assert.True(t, AssertTwiMLEqual(t, res, doc))
+}
+
+func TestAssertTwiMLEqualReportsPaths(t *testing.T) {
+	ft := &fakeT{}
+	doc := `<Response><Gather action="/menu" numDigits="2" timeout="5"><Say voice="alice">Press 2</Say></Gather><Reject/></Response>`
+	assert.False(t, AssertTwiMLEqual(ft, greeting(), doc))
+	assert.Len(t, ft.errors, 1)
+	assert.Contains(t, ft.errors[0], `Gather[0].numDigits: 1 -> 2`)
+	assert.Contains(t, ft.errors[0], `Gather[0]/Say[0]: Press 1 for sales -> Press 2`)
+	assert.Contains(t, ft.errors[0], `- Hangup[0]`)
+	assert.Contains(t, ft.errors[0], `+ Reject[0]`)
+}
+
+func TestAssertGolden(t *testing.T) {
+	AssertGolden(t, "greeting", greeting())
+
+	if !*update {
+		ft := &fakeT{}
+		assert.False(t, AssertGolden(ft, "missing", greeting()))
+	}
+	assert.Nil(t, flag.Lookup("update"), "tests using the package may define their own -update flag")
+}
@@ -0,0 +1,297 @@
+package twimltest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/BTBurke/twiml"
+)
+
+// DefaultMaxRequests limits the number of requests a Simulator sends before giving up
+// on a call flow that never ends
+const DefaultMaxRequests = 50
+
+// RecordingURL is the URL of recordings made by simulated Record verbs
+const RecordingURL = "https://api.twilio.com/2010-04-01/Accounts/AC00000000000000000000000000000000/Recordings/RE00000000000000000000000000000000"
+
+// Input is the response of the simulated caller to a Gather verb
+type Input struct {
+	Digits string
+	Speech string
+}
+
+// Digits returns caller input entered on the keypad
+func Digits(d string) Input {
+	return Input{Digits: d}
+}
+
+// Speech returns caller input spoken to a Gather verb accepting speech
+func Speech(s string) Input {
+	return Input{Speech: s}
+}
+
+// NoInput is caller input that lets a Gather verb time out
+var NoInput = Input{}
+
+// Script describes how the simulated caller and the parties they are connected to behave
+type Script struct {
+	// Inputs are consumed in order by each Gather verb.  Gathers time out once inputs run out.
+	Inputs []Input
+	// DialOutcomes are the DialCallStatus values of each Dial verb, consumed in order.
+	// Dials complete once outcomes run out.
+	DialOutcomes []string
+	// RecordingDuration is the length in seconds of recordings made by Record verbs
+	RecordingDuration int
+	// MaxRequests overrides DefaultMaxRequests when set
+	MaxRequests int
+}
+
+// Event is a single step in the transcript of a simulated call
+type Event struct {
+	// Verb is the TwiML verb that was executed
+	Verb string
+	// Detail is the text spoken, URL played, digits gathered or number dialed
+	Detail string
+}
+
+// String returns the event as a transcript line
+func (e Event) String() string {
+	if e.Detail == "" {
+		return e.Verb
+	}
+	return fmt.Sprintf("%s: %s", e.Verb, e.Detail)
+}
+
+// Result is the outcome of a simulated call
+type Result struct {
+	// Transcript lists every verb executed during the call
+	Transcript []Event
+	// EndedBy is the verb that ended the call, or the empty string when the call ended
+	// because the last response ran out of verbs
+	EndedBy string
+	// Requests are the requests sent to the handler in order
+	Requests []*Request
+}
+
+// Lines returns the transcript as one string per event
+func (r *Result) Lines() []string {
+	lines := make([]string, 0, len(r.Transcript))
+	for _, e := range r.Transcript {
+		lines = append(lines, e.String())
+	}
+	return lines
+}
+
+// Simulator executes the TwiML returned by a handler against a scripted caller, following
+// action and redirect URLs back into the handler until the call ends
+type Simulator struct {
+	Handler http.Handler
+	// URL receives the initial request for the call, defaults to DefaultURL
+	URL    string
+	From   string
+	To     string
+	Script Script
+
+	result *Result
+	inputs []Input
+	dials  []string
+}
+
+// Simulate runs a call from DefaultFrom to DefaultTo against the handler
+func Simulate(h http.Handler, script Script) (*Result, error) {
+	s := &Simulator{Handler: h, Script: script}
+	return s.Run()
+}
+
+// step is the outcome of executing a single verb
+type step int
+
+const (
+	next step = iota
+	fetched
+	ended
+)
+
+// Run simulates the call.  An error is returned when the handler fails or the call
+// exceeds the maximum number of requests.
+func (s *Simulator) Run() (*Result, error) {
+	from, to := s.From, s.To
+	if from == "" {
+		from = DefaultFrom
+	}
+	if to == "" {
+		to = DefaultTo
+	}
+	req := IncomingCall(from, to)
+	if s.URL != "" {
+		req.To(s.URL)
+	}
+	s.result = &Result{}
+	s.inputs = s.Script.Inputs
+	s.dials = s.Script.DialOutcomes
+	max := s.Script.MaxRequests
+	if max == 0 {
+		max = DefaultMaxRequests
+	}
+
+	for req != nil {
+		if len(s.result.Requests) >= max {
+			return s.result, fmt.Errorf("twimltest: call did not end after %d requests", max)
+		}
+		s.result.Requests = append(s.result.Requests, req)
+		res, err := req.Serve(s.Handler)
+		if err != nil {
+			return s.result, err
+		}
+		if req, err = s.execute(req, res); err != nil {
+			return s.result, err
+		}
+	}
+	return s.result, nil
+}
+
+// execute runs the verbs of a response in order, returning the next request or nil if the call ended
+func (s *Simulator) execute(req *Request, res *twiml.Response) (*Request, error) {
+	for _, m := range res.Children {
+		nextReq, st, err := s.verb(req, m)
+		if err != nil {
+			return nil, err
+		}
+		switch st {
+		case fetched:
+			return nextReq, nil
+		case ended:
+			s.result.EndedBy = m.Type()
+			return nil, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Simulator) emit(verb string, detail string) {
+	s.result.Transcript = append(s.result.Transcript, Event{Verb: verb, Detail: detail})
+}
+
+// verb executes a single verb and returns the request to follow when it fetches new TwiML
+func (s *Simulator) verb(req *Request, m twiml.Markup) (*Request, step, error) {
+	switch v := m.(type) {
+	case *twiml.Say:
+		s.emit("Say", v.Text)
+	case *twiml.Play:
+		if v.Digits != "" {
+			s.emit("Play", "digits "+v.Digits)
+		} else {
+			s.emit("Play", v.URL)
+		}
+	case *twiml.Pause:
+		s.emit("Pause", strconv.Itoa(v.Length))
+	case *twiml.Sms:
+		s.emit("Message", v.Text)
+	case *twiml.Leave:
+		s.emit("Leave", "")
+	case *twiml.Hangup:
+		s.emit("Hangup", "")
+		return nil, ended, nil
+	case *twiml.Reject:
+		s.emit("Reject", v.Reason)
+		return nil, ended, nil
+	case *twiml.Enqueue:
+		s.emit("Enqueue", v.QueueName)
+		return nil, ended, nil
+	case *twiml.Redirect:
+		s.emit("Redirect", v.URL)
+		next, err := s.follow(req, req.follow(req.inProgress()), v.URL, v.Method)
+		return next, fetched, err
+	case *twiml.Gather:
+		return s.gather(req, v)
+	case *twiml.Dial:
+		return s.dial(req, v)
+	case *twiml.Record:
+		s.emit("Record", strconv.Itoa(s.Script.RecordingDuration)+"s")
+		cb := req.RecordAction(RecordingURL, s.Script.RecordingDuration)
+		next, err := s.follow(req, cb, v.Action, v.Method)
+		return next, fetched, err
+	default:
+		return nil, next, fmt.Errorf("twimltest: simulator does not support %s", m.Type())
+	}
+	return nil, next, nil
+}
+
+func (s *Simulator) gather(req *Request, g *twiml.Gather) (*Request, step, error) {
+	for _, child := range g.Children {
+		if _, _, err := s.verb(req, child); err != nil {
+			return nil, next, err
+		}
+	}
+	var in Input
+	if len(s.inputs) > 0 {
+		in, s.inputs = s.inputs[0], s.inputs[1:]
+	}
+	var cb *Request
+	switch {
+	case in.Digits != "":
+		s.emit("Gather", in.Digits)
+		cb = req.GatherAction(in.Digits)
+	case in.Speech != "":
+		s.emit("Gather", in.Speech)
+		cb = req.SpeechAction(in.Speech, 0.9)
+	default:
+		// Twilio continues with the next verb when the caller enters nothing
+		s.emit("Gather", "timeout")
+		return nil, next, nil
+	}
+	nextReq, err := s.follow(req, cb, g.Action, g.Method)
+	return nextReq, fetched, err
+}
+
+func (s *Simulator) dial(req *Request, d *twiml.Dial) (*Request, step, error) {
+	targets := []string{}
+	if d.Number != "" {
+		targets = append(targets, d.Number)
+	}
+	for _, child := range d.Children {
+		switch n := child.(type) {
+		case *twiml.Number:
+			targets = append(targets, n.Number)
+		case *twiml.Client:
+			targets = append(targets, "client:"+n.Name+n.Identity)
+		case *twiml.Sip:
+			targets = append(targets, n.Address)
+		case *twiml.Queue:
+			targets = append(targets, "queue:"+n.Name)
+		case *twiml.Conference:
+			targets = append(targets, "conference:"+n.ConferenceName)
+		}
+	}
+	status := twiml.Completed
+	if len(s.dials) > 0 {
+		status, s.dials = s.dials[0], s.dials[1:]
+	}
+	s.emit("Dial", fmt.Sprintf("%s (%s)", strings.Join(targets, ", "), status))
+	if d.Action == "" {
+		// without an action Twilio continues with the next verb after the dial ends
+		return nil, next, nil
+	}
+	nextReq, err := s.follow(req, req.DialAction(status), d.Action, d.Method)
+	return nextReq, fetched, err
+}
+
+// follow sends the callback to target resolved against the URL of the current request.
+// An empty target requests the current URL again.
+func (s *Simulator) follow(req *Request, cb *Request, target string, method string) (*Request, error) {
+	base, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	if method == "" {
+		method = http.MethodPost
+	}
+	return cb.To(base.ResolveReference(ref).String()).WithMethod(method), nil
+}
@@ -0,0 +1,107 @@
+package twimltest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/BTBurke/twiml"
+	"github.com/stretchr/testify/assert"
+)
+
+func respond(w http.ResponseWriter, verbs ...twiml.Markup) {
+	res := twiml.NewResponse()
+	res.Add(verbs...)
+	b, err := res.Encode()
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	w.Write(b)
+}
+
+// menu is a small IVR that offers sales or support and leaves a voicemail when support is busy
+func menu() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/twiml", func(w http.ResponseWriter, r *http.Request) {
+		g := &twiml.Gather{Action: "/menu", NumDigits: 1}
+		g.Add(&twiml.Say{Text: "Press 1 for sales or 2 for support"})
+		respond(w, g, &twiml.Redirect{URL: "/twiml"})
+	})
+	mux.HandleFunc("/menu", func(w http.ResponseWriter, r *http.Request) {
+		var gr twiml.GatherActionRequest
+		twiml.Bind(&gr, r)
+		switch gr.Digits {
+		case "1":
+			respond(w, &twiml.Say{Text: "Connecting you to sales"}, &twiml.Dial{Number: "+15550001111"})
+		case "2":
+			respond(w, &twiml.Dial{Number: "+15550002222", Action: "/support"})
+		default:
+			respond(w, &twiml.Redirect{URL: "/twiml"})
+		}
+	})
+	mux.HandleFunc("/support", func(w http.ResponseWriter, r *http.Request) {
+		var dr twiml.DialActionRequest
+		twiml.Bind(&dr, r)
+		if dr.DialCallStatus == twiml.Completed {
+			respond(w, &twiml.Hangup{})
+			return
+		}
+		respond(w, &twiml.Say{Text: "Leave a message"}, &twiml.Record{Action: "/recorded", MaxLength: 30})
+	})
+	mux.HandleFunc("/recorded", func(w http.ResponseWriter, r *http.Request) {
+		var rr twiml.RecordActionRequest
+		twiml.Bind(&rr, r)
+		if rr.RecordingURL != RecordingURL {
+			http.Error(w, "missing recording", 400)
+			return
+		}
+		respond(w, &twiml.Say{Text: "Goodbye"}, &twiml.Hangup{})
+	})
+	return mux
+}
+
+func TestSimulateSales(t *testing.T) {
+	res, err := Simulate(menu(), Script{Inputs: []Input{Digits("1")}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"Say: Press 1 for sales or 2 for support",
+		"Gather: 1",
+		"Say: Connecting you to sales",
+		"Dial: +15550001111 (completed)",
+	}, res.Lines())
+	assert.Equal(t, "", res.EndedBy)
+	assert.Len(t, res.Requests, 2)
+}
+
+func TestSimulateVoicemail(t *testing.T) {
+	res, err := Simulate(menu(), Script{
+		Inputs:            []Input{NoInput, Digits("9"), Digits("2")},
+		DialOutcomes:      []string{twiml.Busy},
+		RecordingDuration: 12,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"Say: Press 1 for sales or 2 for support",
+		"Gather: timeout",
+		"Redirect: /twiml",
+		"Say: Press 1 for sales or 2 for support",
+		"Gather: 9",
+		"Redirect: /twiml",
+		"Say: Press 1 for sales or 2 for support",
+		"Gather: 2",
+		"Dial: +15550002222 (busy)",
+		"Say: Leave a message",
+		"Record: 12s",
+		"Say: Goodbye",
+		"Hangup",
+	}, res.Lines())
+	assert.Equal(t, "Hangup", res.EndedBy)
+	assert.Equal(t, "https://example.com/recorded", res.Requests[len(res.Requests)-1].URL)
+}
+
+func TestSimulateEndlessLoop(t *testing.T) {
+	s := &Simulator{Handler: menu(), Script: Script{MaxRequests: 5}}
+	res, err := s.Run()
+	assert.Error(t, err)
+	assert.Len(t, res.Requests, 5)
+}
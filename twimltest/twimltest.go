@@ -0,0 +1,115 @@
+// Package twimltest provides utilities for testing handlers that respond to the
+// Twilio API with TwiML.
+package twimltest
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/BTBurke/twiml"
+)
+
+// AuthToken is the auth token used to sign simulated requests
+const AuthToken = "twimltest-auth-token"
+
+// DefaultURL is the URL simulated requests are sent to unless another is set with To
+const DefaultURL = "https://example.com/twiml"
+
+// Request is a simulated callback request from the Twilio API
+type Request struct {
+	Method    string
+	URL       string
+	Params    url.Values
+	AuthToken string
+}
+
+// NewRequest creates a POST request with the parameters of a callback request struct,
+// such as twiml.VoiceRequest or twiml.DialActionRequest
+func NewRequest(cbRequest interface{}) *Request {
+	params := url.Values{}
+	if err := encoder.Encode(cbRequest, params); err != nil {
+		panic(fmt.Sprintf("twimltest: can not encode %T: %s", cbRequest, err))
+	}
+	for key, values := range params {
+		if len(values) == 1 && values[0] == "" {
+			delete(params, key)
+		}
+	}
+	return &Request{
+		Method:    http.MethodPost,
+		URL:       DefaultURL,
+		Params:    params,
+		AuthToken: AuthToken,
+	}
+}
+
+// Set sets a request parameter, replacing any existing value
+func (r *Request) Set(key string, value string) *Request {
+	r.Params.Set(key, value)
+	return r
+}
+
+// To sets the URL of the request
+func (r *Request) To(rawURL string) *Request {
+	r.URL = rawURL
+	return r
+}
+
+// WithMethod sets the HTTP method of the request.  Parameters of GET requests are sent
+// in the query string.
+func (r *Request) WithMethod(method string) *Request {
+	r.Method = method
+	return r
+}
+
+// HTTPRequest returns the request as it would be sent by Twilio, signed with the
+// request's auth token
+func (r *Request) HTTPRequest() *http.Request {
+	if r.Method == http.MethodGet {
+		u := r.URL
+		if len(r.Params) > 0 {
+			if strings.Contains(u, "?") {
+				u += "&" + r.Params.Encode()
+			} else {
+				u += "?" + r.Params.Encode()
+			}
+		}
+		req := httptest.NewRequest(r.Method, u, nil)
+		req.Header.Set(twiml.SignatureHeader, twiml.Signature(r.AuthToken, u, nil))
+		return req
+	}
+
+	body := r.Params.Encode()
+	req := httptest.NewRequest(r.Method, r.URL, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	req.Header.Set(twiml.SignatureHeader, twiml.Signature(r.AuthToken, r.URL, r.Params))
+	return req
+}
+
+// Record sends the request to the handler and returns the recorded response
+func (r *Request) Record(h http.Handler) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r.HTTPRequest())
+	return rec
+}
+
+// Serve sends the request to the handler and decodes the TwiML it returns.  An empty
+// body decodes to a response without any verbs.  An error is returned when the handler
+// responds with a non-2xx status or invalid TwiML.
+func (r *Request) Serve(h http.Handler) (*twiml.Response, error) {
+	rec := r.Record(h)
+	if rec.Code < 200 || rec.Code > 299 {
+		return nil, fmt.Errorf("twimltest: %s %s returned status %d", r.Method, r.URL, rec.Code)
+	}
+	body := bytes.TrimSpace(rec.Body.Bytes())
+	if len(body) == 0 {
+		return twiml.NewResponse(), nil
+	}
+	return twiml.Decode(body)
+}
@@ -0,0 +1,89 @@
+package twimltest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/BTBurke/twiml"
+	"github.com/stretchr/testify/assert"
+)
+
+func forwardHandler(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, twiml.VerifySignature(AuthToken, DefaultURL, r))
+
+		var vr twiml.VoiceRequest
+		if err := twiml.Bind(&vr, r); err != nil {
+			http.Error(w, http.StatusText(400), 400)
+			return
+		}
+		res := twiml.NewResponse()
+		res.Add(&twiml.Dial{Number: "+15558675309", CallerID: vr.To})
+		b, err := res.Encode()
+		if err != nil {
+			http.Error(w, http.StatusText(502), 502)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write(b)
+	})
+}
+
+func TestIncomingCall(t *testing.T) {
+	res, err := IncomingCall("+15551112222", "+15553334444").Serve(forwardHandler(t))
+	assert.NoError(t, err)
+	assert.Equal(t, []twiml.Markup{&twiml.Dial{Number: "+15558675309", CallerID: "+15553334444"}}, res.Children)
+}
+
+func TestServeErrorStatus(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, http.StatusText(500), 500)
+	})
+	_, err := DialAction(twiml.Busy).Serve(h)
+	assert.Error(t, err)
+}
+
+func TestServeEmptyBody(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	})
+	res, err := GatherAction("1").Serve(h)
+	assert.NoError(t, err)
+	assert.Empty(t, res.Children)
+}
+
+func TestCallbacksBindStrictly(t *testing.T) {
+	binder := twiml.NewBinder(twiml.Strict())
+	call := IncomingCall("+15551112222", "+15553334444")
+
+	var dr twiml.DialActionRequest
+	assert.NoError(t, binder.Bind(&dr, call.DialAction(twiml.NoAnswer).HTTPRequest()))
+	assert.Equal(t, twiml.NoAnswer, dr.DialCallStatus)
+	assert.Equal(t, call.Call().CallSid, dr.CallSid)
+	assert.Equal(t, twiml.InProgress, dr.CallStatus)
+
+	var gr twiml.GatherActionRequest
+	assert.NoError(t, binder.Bind(&gr, call.GatherAction("42").HTTPRequest()))
+	assert.Equal(t, "42", gr.Digits)
+
+	var rr twiml.RecordActionRequest
+	assert.NoError(t, binder.Bind(&rr, call.RecordAction("https://example.com/r.mp3", 8).HTTPRequest()))
+	assert.Equal(t, 8, rr.RecordingDuration)
+
+	var sr twiml.RecordingStatusCallbackRequest
+	assert.NoError(t, binder.Bind(&sr, call.RecordingComplete("https://example.com/r.mp3", 8).HTTPRequest()))
+	assert.Equal(t, "completed", sr.RecordingStatus)
+
+	var tr twiml.TranscribeCallbackRequest
+	assert.NoError(t, binder.Bind(&tr, call.Transcription("hello").HTTPRequest()))
+	assert.Equal(t, "hello", tr.TranscriptionText)
+}
+
+func TestGetRequest(t *testing.T) {
+	req := GatherAction("7").WithMethod(http.MethodGet).To("https://example.com/menu?step=1").HTTPRequest()
+	assert.NoError(t, twiml.VerifySignature(AuthToken, req.URL.String(), req))
+
+	var gr twiml.GatherActionRequest
+	assert.NoError(t, twiml.Bind(&gr, req))
+	assert.Equal(t, "7", gr.Digits)
+}
@@ -0,0 +1,39 @@
+package twiml
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// urlHolder is implemented by verbs with webhook URLs, such as actions and status callbacks
+type urlHolder interface {
+	urls() []*string
+}
+
+// ResolveURLs converts the relative webhook URLs in the response, such as actions, status
+// callbacks and the URLs of Play and Redirect, into absolute URLs resolved against base.
+// Absolute URLs are unchanged.  Write URLs without a leading slash so that a flow can be
+// mounted under any prefix:
+//
+//	base, _ := url.Parse("https://example.com/ivr/")
+//	r.Add(&Redirect{URL: "menu"})
+//	r.ResolveURLs(base) // https://example.com/ivr/menu
+func (r *Response) ResolveURLs(base *url.URL) error {
+	return Walk(r, func(path string, m Markup) error {
+		h, ok := m.(urlHolder)
+		if !ok {
+			return nil
+		}
+		for _, f := range h.urls() {
+			if *f == "" {
+				continue
+			}
+			u, err := url.Parse(*f)
+			if err != nil {
+				return fmt.Errorf("%s: %s", path, err)
+			}
+			*f = base.ResolveReference(u).String()
+		}
+		return nil
+	})
+}
@@ -0,0 +1,64 @@
+package twiml
+
+import (
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("URLs", func() {
+	It("validates URL attributes", func() {
+		Expect(AllowedURL("")).To(BeTrue())
+		Expect(AllowedURL("menu")).To(BeTrue())
+		Expect(AllowedURL("/ivr/menu?step=2")).To(BeTrue())
+		Expect(AllowedURL("https://example.com/menu")).To(BeTrue())
+		Expect(AllowedURL("HTTP://example.com/menu")).To(BeTrue())
+		Expect(AllowedURL("ftp://example.com/menu")).To(BeFalse())
+		Expect(AllowedURL("mailto:someone@example.com")).To(BeFalse())
+		Expect(AllowedURL("http://example.com/%zz")).To(BeFalse())
+		Expect(AllowedURL("not a url")).To(BeFalse())
+		Expect(AllowedURL("https://example.com/hold music.mp3")).To(BeFalse())
+		Expect(AllowedURL("menu\tnext")).To(BeFalse())
+		Expect(AllowedURL("/ivr/menu?name=Tom+%26+Jerry")).To(BeTrue())
+	})
+
+	It("rejects verbs with invalid URLs", func() {
+		r := NewResponse()
+		r.Add(&Redirect{URL: "javascript:alert(1)"})
+		Expect(r.Validate()).ToNot(Succeed())
+	})
+
+	It("resolves relative URLs against a base", func() {
+		r := NewResponse()
+		g := &Gather{Action: "menu", PartialResultCallback: "https://hooks.example.com/partial"}
+		g.Add(&Play{URL: "audio/welcome.mp3"})
+		d := &Dial{Action: "/dial-status"}
+		d.Add(&Number{Number: "+15555555555", URL: "whisper?lang=en"})
+		r.Add(g, d, &Record{TranscribeCallback: "../transcripts"}, &Redirect{URL: "menu"}, &Hangup{})
+
+		base, err := url.Parse("https://example.com/flows/ivr/")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(r.ResolveURLs(base)).To(Succeed())
+
+		Expect(g.Action).To(Equal("https://example.com/flows/ivr/menu"))
+		Expect(g.PartialResultCallback).To(Equal("https://hooks.example.com/partial"))
+		Expect(g.Children[0].(*Play).URL).To(Equal("https://example.com/flows/ivr/audio/welcome.mp3"))
+		Expect(d.Action).To(Equal("https://example.com/dial-status"))
+		Expect(d.Children[0].(*Number).URL).To(Equal("https://example.com/flows/ivr/whisper?lang=en"))
+		Expect(r.Children[2].(*Record).TranscribeCallback).To(Equal("https://example.com/flows/transcripts"))
+		Expect(r.Children[3].(*Redirect).URL).To(Equal("https://example.com/flows/ivr/menu"))
+		Expect(r.Validate()).To(Succeed())
+	})
+
+	It("reports URLs that do not parse", func() {
+		r := NewResponse()
+		g := &Gather{}
+		g.Add(&Play{URL: "%zz"})
+		r.Add(g)
+		base, _ := url.Parse("https://example.com/")
+		err := r.ResolveURLs(base)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix("Gather[0]/Play[0]: "))
+	})
+})
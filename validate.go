@@ -2,8 +2,10 @@ package twiml
 
 import (
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
+	"unicode"
 )
 
 // Validate aggregates the results of individual validation functions and returns true
@@ -85,6 +87,25 @@ func NumericOrWait(field string) bool {
 	return matched
 }
 
+// AllowedURL validates that a field is a relative URL or an absolute http or https URL (or empty
+// string for optional fields).  Spaces and control characters must be escaped.
+func AllowedURL(field string) bool {
+	if field == "" {
+		return true
+	}
+	if strings.IndexFunc(field, func(r rune) bool { return r == ' ' || unicode.IsControl(r) }) >= 0 {
+		return false
+	}
+	u, err := url.Parse(field)
+	if err != nil {
+		return false
+	}
+	if u.Scheme != "" && u.Host == "" {
+		return false
+	}
+	return OneOf(strings.ToLower(u.Scheme), "", "http", "https")
+}
+
 // NumericOpt validates that the field is numeric or empty string (for optional fields)
 func NumericOpt(field string) bool {
 	if field == "" {
@@ -159,3 +180,9 @@ func AllowedCallbackEvent(events string, callbackValidator *regexp.Regexp) bool
 	}
 	return callbackValidator.MatchString(events)
 }
+
+// validParameters checks that if parameters are set, name is empty and we have an identity
+func (c *Client) validParameters() bool {
+	// cannot have both of these be true at once
+	return len(c.Children) == 0 || (len(c.Name) == 0 || len(c.Identity) != 0)
+}
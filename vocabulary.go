@@ -1,3 +1,5 @@
+// Code generated by twimlgen from vocabulary.yaml. DO NOT EDIT.
+
 package twiml
 
 import (
@@ -5,6 +7,45 @@ import (
 	"fmt"
 )
 
+// verbs maps TwiML element names to a constructor for the markup they decode into
+var verbs = map[string]func() Markup{
+	"Client":     func() Markup { return &Client{} },
+	"Parameter":  func() Markup { return &Parameter{} },
+	"Conference": func() Markup { return &Conference{} },
+	"Dial":       func() Markup { return &Dial{} },
+	"Enqueue":    func() Markup { return &Enqueue{} },
+	"Hangup":     func() Markup { return &Hangup{} },
+	"Leave":      func() Markup { return &Leave{} },
+	"Message":    func() Markup { return &Sms{} },
+	"Media":      func() Markup { return &Media{} },
+	"Number":     func() Markup { return &Number{} },
+	"Pause":      func() Markup { return &Pause{} },
+	"Play":       func() Markup { return &Play{} },
+	"Queue":      func() Markup { return &Queue{} },
+	"Record":     func() Markup { return &Record{} },
+	"Redirect":   func() Markup { return &Redirect{} },
+	"Reject":     func() Markup { return &Reject{} },
+	"Say":        func() Markup { return &Say{} },
+	"Sip":        func() Markup { return &Sip{} },
+	"Gather":     func() Markup { return &Gather{} },
+}
+
+// responseVerbs are the types of the verbs allowed directly in a Response
+var responseVerbs = map[string]bool{
+	"Dial":     true,
+	"Enqueue":  true,
+	"Hangup":   true,
+	"Leave":    true,
+	"Sms":      true,
+	"Pause":    true,
+	"Play":     true,
+	"Record":   true,
+	"Redirect": true,
+	"Reject":   true,
+	"Say":      true,
+	"Gather":   true,
+}
+
 // Twilio Client TwiML
 type Client struct {
 	XMLName  xml.Name `xml:"Client"`
@@ -19,41 +60,51 @@ type Client struct {
 	Children             []Markup `xml:",omitempty"`
 }
 
-// Add adds noun structs to a Client response as children
+// Add nests markup in the Client.  Valid children: Parameter
 func (c *Client) Add(ml ...Markup) {
-	for _, s := range ml {
-		c.Children = append(c.Children, s)
-	}
-	return
+	c.Children = append(c.Children, ml...)
+}
+
+// Kids returns the markup nested in the Client
+func (c *Client) Kids() []Markup {
+	return c.Children
+}
+
+// SetKids replaces the markup nested in the Client
+func (c *Client) SetKids(ml []Markup) {
+	c.Children = ml
 }
 
 // Validate returns an error if the TwiML is constructed improperly
 func (c *Client) Validate() error {
-	var ok bool
-
-	if ok = Validate(AllowedMethod(c.Method)); !ok {
-		return fmt.Errorf("Client markup failed validation")
+	var errs []error
+	for _, m := range c.Children {
+		switch m.Type() {
+		case "Parameter":
+			if err := m.Validate(); err != nil {
+				errs = append(errs, err)
+			}
+		default:
+			return fmt.Errorf("Not a valid verb as child of %s: '%T'", c.Type(), m)
+		}
 	}
-
-	// require either name or identity
-	if ok = len(c.Name) > 0 || len(c.Identity) > 0; !ok {
-		return fmt.Errorf("Client markup failed validation")
+	ok := Validate(
+		AllowedMethod(c.Method),
+		AllowedURL(c.URL),
+		AllowedURL(c.StatusCallback),
+		len(c.Name) > 0 || len(c.Identity) > 0,
+		c.validParameters(),
+	)
+	if !ok {
+		errs = append(errs, fmt.Errorf("%s markup failed validation", c.Type()))
 	}
-
-	if ok = c.validParameters(); !ok {
-		return fmt.Errorf("Client markup failed validation")
+	if len(errs) > 0 {
+		return ValidationError{errs}
 	}
-
 	return nil
 }
 
-// validParameters checks that if parameters are set, name is empty and we have an identity
-func (c *Client) validParameters() bool {
-	// cannot have both of these be true at once
-	return len(c.Children) == 0 || (len(c.Name) == 0 || len(c.Identity) != 0)
-}
-
-// Type returns the XML name of the verb
+// Type returns the name of the verb
 func (c *Client) Type() string {
 	return "Client"
 }
@@ -65,61 +116,70 @@ type Parameter struct {
 	Value   string   `xml:"value,attr,omitempty"`
 }
 
-func (p Parameter) Type() string {
-	return "Parameter"
-}
-
-// Validate <Parameter> noun
+// Validate returns an error if the TwiML is constructed improperly
 func (p Parameter) Validate() error {
-	if ok := Validate(Required(p.Name), Required(p.Value)); !ok {
-		return fmt.Errorf("parameter markup failed validation")
+	ok := Validate(
+		Required(p.Name),
+		Required(p.Value),
+	)
+	if !ok {
+		return fmt.Errorf("%s markup failed validation", p.Type())
 	}
-
 	return nil
 }
 
+// Type returns the name of the verb
+func (p Parameter) Type() string {
+	return "Parameter"
+}
+
 // Conference TwiML
 type Conference struct {
-	XMLName                       xml.Name `xml:"Conference"`
-	ConferenceName                string   `xml:",chardata"`
-	Muted                         bool     `xml:"muted,attr,omitempty"`
-	Beep                          string   `xml:"beep,attr,omitempty"`
-	StartConferenceOnEnter        bool     `xml:"startConferenceOnEnter,attr,omitempty"`
-	EndConferenceOnExit           bool     `xml:"endConferenceOnExit,attr,omitempty"`
-	WaitURL                       string   `xml:"waitUrl,attr,omitempty"`
-	WaitMethod                    string   `xml:"waitMethod,attr,omitempty"`
-	MaxParticipants               int      `xml:"maxParticipants,attr,omitempty"`
-	Record                        string   `xml:"record,attr,omitempty"`
-	Region                        string   `xml:"region,attr,omitempty"`
-	Trim                          string   `xml:"trim,attr,omitempty"`
-	Coach                         string   `xml:"coach,attr,omitempty"`
-	StatusCallbackEvent           string   `xml:"statusCallbackEvent,attr,omitempty"`
-	StatusCallback                string   `xml:"statusCallback,attr,omitempty"`
-	StatusCallbackMethod          string   `xml:"statusCallbackMethod,attr,omitempty"`
-	RecordingStatusCallback       string   `xml:"recordingStatusCallback,attr,omitempty"`
-	RecordingStatusCallbackMethod string   `xml:"recordingStatusCallbackMethod,attr,omitempty"`
-	RecordingStatusCallbackEvent  string   `xml:"recordingStatusCallbackEvent,attr,omitempty"`
-	EventCallbackURL              string   `xml:"eventCallbackUrl,attr,omitempty"`
+	XMLName        xml.Name `xml:"Conference"`
+	ConferenceName string   `xml:",chardata"`
+
+	Muted                         bool   `xml:"muted,attr,omitempty"`
+	Beep                          string `xml:"beep,attr,omitempty"`
+	StartConferenceOnEnter        bool   `xml:"startConferenceOnEnter,attr,omitempty"`
+	EndConferenceOnExit           bool   `xml:"endConferenceOnExit,attr,omitempty"`
+	WaitURL                       string `xml:"waitUrl,attr,omitempty"`
+	WaitMethod                    string `xml:"waitMethod,attr,omitempty"`
+	MaxParticipants               int    `xml:"maxParticipants,attr,omitempty"`
+	Record                        string `xml:"record,attr,omitempty"`
+	Region                        string `xml:"region,attr,omitempty"`
+	Trim                          string `xml:"trim,attr,omitempty"`
+	Coach                         string `xml:"coach,attr,omitempty"`
+	StatusCallbackEvent           string `xml:"statusCallbackEvent,attr,omitempty"`
+	StatusCallback                string `xml:"statusCallback,attr,omitempty"`
+	StatusCallbackMethod          string `xml:"statusCallbackMethod,attr,omitempty"`
+	RecordingStatusCallback       string `xml:"recordingStatusCallback,attr,omitempty"`
+	RecordingStatusCallbackMethod string `xml:"recordingStatusCallbackMethod,attr,omitempty"`
+	RecordingStatusCallbackEvent  string `xml:"recordingStatusCallbackEvent,attr,omitempty"`
+	EventCallbackURL              string `xml:"eventCallbackUrl,attr,omitempty"`
 }
 
 // Validate returns an error if the TwiML is constructed improperly
 func (c *Conference) Validate() error {
 	ok := Validate(
 		OneOfOpt(c.Beep, "true", "false", "onEnter", "onExit"),
+		AllowedURL(c.WaitURL),
 		AllowedMethod(c.WaitMethod),
 		OneOfOpt(c.Record, "do-not-record", "record-from-start"),
 		OneOfOpt(c.Trim, "trim-silence", "do-not-trim"),
 		AllowedCallbackEvent(c.StatusCallbackEvent, ConferenceCallbackEvents),
+		AllowedURL(c.StatusCallback),
 		AllowedMethod(c.StatusCallbackMethod),
+		AllowedURL(c.RecordingStatusCallback),
 		AllowedMethod(c.RecordingStatusCallbackMethod),
+		AllowedURL(c.EventCallbackURL),
 	)
 	if !ok {
-		return fmt.Errorf("Conference markup failed validation")
+		return fmt.Errorf("%s markup failed validation", c.Type())
 	}
 	return nil
 }
 
-// Type returns the XML name of the verb
+// Type returns the name of the verb
 func (c *Conference) Type() string {
 	return "Conference"
 }
@@ -127,84 +187,96 @@ func (c *Conference) Type() string {
 // Dial TwiML
 type Dial struct {
 	XMLName xml.Name `xml:"Dial"`
+	Number  string   `xml:",chardata"`
 
-	Action         string `xml:"action,attr,omitempty"`
-	AnswerOnBridge bool   `xml:"answerOnBridge,attr,omitempty"`
-	CallerID       string `xml:"callerId,attr,omitempty"`
-	HangupOnStar   bool   `xml:"hangupOnStar,attr,omitempty"`
-	Method         string `xml:"method,attr,omitempty"`
-
-	Record                        string `xml:"record,attr,omitempty"`
-	RecordingStatusCallback       string `xml:"recordingStatusCallback,attr,omitempty"`
-	RecordingStatusCallbackMethod string `xml:"recordingStatusCallbackMethod,attr,omitempty"`
-	RecordingStatusCallbackEvent  string `xml:"recordingStatusCallbackEvent,attr,omitempty"`
-
-	RingTone string `xml:"ringTone,attr,omitempty"`
+	Action                        string   `xml:"action,attr,omitempty"`
+	AnswerOnBridge                bool     `xml:"answerOnBridge,attr,omitempty"`
+	CallerID                      string   `xml:"callerId,attr,omitempty"`
+	HangupOnStar                  bool     `xml:"hangupOnStar,attr,omitempty"`
+	Method                        string   `xml:"method,attr,omitempty"`
+	Record                        string   `xml:"record,attr,omitempty"`
+	RecordingStatusCallback       string   `xml:"recordingStatusCallback,attr,omitempty"`
+	RecordingStatusCallbackMethod string   `xml:"recordingStatusCallbackMethod,attr,omitempty"`
+	RecordingStatusCallbackEvent  string   `xml:"recordingStatusCallbackEvent,attr,omitempty"`
+	RecordingTrack                string   `xml:"recordingTrack,attr,omitempty"`
+	ReferURL                      string   `xml:"referUrl,attr,omitempty"`
+	ReferMethod                   string   `xml:"referMethod,attr,omitempty"`
+	RingTone                      string   `xml:"ringTone,attr,omitempty"`
+	Sequential                    bool     `xml:"sequential,attr,omitempty"`
+	Timeout                       int      `xml:"timeout,attr,omitempty"`
+	TimeLimit                     int      `xml:"timeLimit,attr,omitempty"`
+	Trim                          string   `xml:"trim,attr,omitempty"`
+	Children                      []Markup `xml:",omitempty"`
+}
 
-	Timeout   int `xml:"timeout,attr,omitempty"`
-	TimeLimit int `xml:"timeLimit,attr,omitempty"`
+// Add nests markup in the Dial.  Valid children: Client, Conference, Number, Queue, Sip
+func (d *Dial) Add(ml ...Markup) {
+	d.Children = append(d.Children, ml...)
+}
 
-	Trim string `xml:"trim,attr,omitempty"`
+// Kids returns the markup nested in the Dial
+func (d *Dial) Kids() []Markup {
+	return d.Children
+}
 
-	Number   string   `xml:",chardata"`
-	Children []Markup `xml:",omitempty"`
+// SetKids replaces the markup nested in the Dial
+func (d *Dial) SetKids(ml []Markup) {
+	d.Children = ml
 }
 
 // Validate returns an error if the TwiML is constructed improperly
 func (d *Dial) Validate() error {
 	var errs []error
-	for _, s := range d.Children {
-		switch t := s.Type(); t {
-		default:
-			return fmt.Errorf("Not a valid verb under Dial: '%T'", s)
+	for _, m := range d.Children {
+		switch m.Type() {
 		case "Client", "Conference", "Number", "Queue", "Sip":
-			if childErr := s.Validate(); childErr != nil {
-				errs = append(errs, childErr)
+			if err := m.Validate(); err != nil {
+				errs = append(errs, err)
 			}
+		default:
+			return fmt.Errorf("Not a valid verb as child of %s: '%T'", d.Type(), m)
 		}
 	}
-
 	ok := Validate(
-		OneOfOpt(d.Method, "GET", "POST"),
+		AllowedURL(d.Action),
+		AllowedMethod(d.Method),
+		AllowedURL(d.RecordingStatusCallback),
+		OneOfOpt(d.RecordingTrack, "both", "inbound", "outbound"),
+		AllowedURL(d.ReferURL),
+		AllowedMethod(d.ReferMethod),
 	)
 	if !ok {
-		errs = append(errs, fmt.Errorf("Dial did not pass validation"))
+		errs = append(errs, fmt.Errorf("%s markup failed validation", d.Type()))
 	}
-
 	if len(errs) > 0 {
 		return ValidationError{errs}
 	}
 	return nil
 }
 
-// Add adds noun structs to a Dial response as children
-func (d *Dial) Add(ml ...Markup) {
-	for _, s := range ml {
-		d.Children = append(d.Children, s)
-	}
-	return
-}
-
-// Type returns the XML name of the verb
+// Type returns the name of the verb
 func (d *Dial) Type() string {
 	return "Dial"
 }
 
 // Enqueue TwiML
 type Enqueue struct {
-	XMLName       xml.Name `xml:"Enqueue"`
-	Action        string   `xml:"action,attr,omitempty"`
-	Method        string   `xml:"method,attr,omitempty"`
-	WaitURL       string   `xml:"waitUrl,attr,omitempty"`
-	WaitURLMethod string   `xml:"waitUrlMethod,attr,omitempty"`
-	WorkflowSid   string   `xml:"workflowSid,attr,omitempty"`
-	QueueName     string   `xml:",chardata"`
+	XMLName   xml.Name `xml:"Enqueue"`
+	QueueName string   `xml:",chardata"`
+
+	Action        string `xml:"action,attr,omitempty"`
+	Method        string `xml:"method,attr,omitempty"`
+	WaitURL       string `xml:"waitUrl,attr,omitempty"`
+	WaitURLMethod string `xml:"waitUrlMethod,attr,omitempty"`
+	WorkflowSid   string `xml:"workflowSid,attr,omitempty"`
 }
 
 // Validate returns an error if the TwiML is constructed improperly
 func (e *Enqueue) Validate() error {
 	ok := Validate(
+		AllowedURL(e.Action),
 		AllowedMethod(e.Method),
+		AllowedURL(e.WaitURL),
 		AllowedMethod(e.WaitURLMethod),
 	)
 	if !ok {
@@ -213,7 +285,7 @@ func (e *Enqueue) Validate() error {
 	return nil
 }
 
-// Type returns the XML name of the verb
+// Type returns the name of the verb
 func (e *Enqueue) Type() string {
 	return "Enqueue"
 }
@@ -228,7 +300,7 @@ func (h *Hangup) Validate() error {
 	return nil
 }
 
-// Type returns the XML name of the verb
+// Type returns the name of the verb
 func (h *Hangup) Type() string {
 	return "Hangup"
 }
@@ -243,53 +315,111 @@ func (l *Leave) Validate() error {
 	return nil
 }
 
-// Type returns the XML name of the verb
+// Type returns the name of the verb
 func (l *Leave) Type() string {
 	return "Leave"
 }
 
-// Sms TwiML sends an SMS message. Text is required.  See the Twilio docs
-// for an explanation of the default values of to and from.
+// Sms TwiML sends an SMS message.  Text or at least one Media is required.  See the
+// Twilio docs for an explanation of the default values of to and from.
 type Sms struct {
-	XMLName        xml.Name `xml:"Message"`
+	XMLName xml.Name `xml:"Message"`
+	Text    string   `xml:",chardata"`
+
 	To             string   `xml:"to,attr,omitempty"`
 	From           string   `xml:"from,attr,omitempty"`
 	Action         string   `xml:"action,attr,omitempty"`
 	Method         string   `xml:"method,attr,omitempty"`
 	StatusCallback string   `xml:"statusCallback,attr,omitempty"`
-	Text           string   `xml:",chardata"`
+	Children       []Markup `xml:",omitempty"`
+}
+
+// Add nests markup in the Sms.  Valid children: Media
+func (s *Sms) Add(ml ...Markup) {
+	s.Children = append(s.Children, ml...)
+}
+
+// Kids returns the markup nested in the Sms
+func (s *Sms) Kids() []Markup {
+	return s.Children
+}
+
+// SetKids replaces the markup nested in the Sms
+func (s *Sms) SetKids(ml []Markup) {
+	s.Children = ml
 }
 
 // Validate returns an error if the TwiML is constructed improperly
 func (s *Sms) Validate() error {
+	var errs []error
+	for _, m := range s.Children {
+		switch m.Type() {
+		case "Media":
+			if err := m.Validate(); err != nil {
+				errs = append(errs, err)
+			}
+		default:
+			return fmt.Errorf("Not a valid verb as child of %s: '%T'", s.Type(), m)
+		}
+	}
 	ok := Validate(
+		AllowedURL(s.Action),
 		AllowedMethod(s.Method),
-		Required(s.Text),
+		AllowedURL(s.StatusCallback),
+		len(s.Text) > 0 || len(s.Children) > 0,
 	)
 	if !ok {
-		return fmt.Errorf("%s markup failed validation", s.Type())
+		errs = append(errs, fmt.Errorf("%s markup failed validation", s.Type()))
+	}
+	if len(errs) > 0 {
+		return ValidationError{errs}
 	}
 	return nil
 }
 
-// Type returns the XML name of the verb
+// Type returns the name of the verb
 func (s *Sms) Type() string {
 	return "Sms"
 }
 
+// Media TwiML adds an image or other media to a Message by URL
+type Media struct {
+	XMLName xml.Name `xml:"Media"`
+	URL     string   `xml:",chardata"`
+}
+
+// Validate returns an error if the TwiML is constructed improperly
+func (m *Media) Validate() error {
+	ok := Validate(
+		AllowedURL(m.URL),
+		Required(m.URL),
+	)
+	if !ok {
+		return fmt.Errorf("%s markup failed validation", m.Type())
+	}
+	return nil
+}
+
+// Type returns the name of the verb
+func (m *Media) Type() string {
+	return "Media"
+}
+
 // Number TwiML
 type Number struct {
-	XMLName    xml.Name `xml:"Number"`
-	SendDigits string   `xml:"sendDigits,attr,omitempty"`
-	URL        string   `xml:"url,attr,omitempty"`
-	Method     string   `xml:"method,attr,omitempty"`
-	Number     string   `xml:",chardata"`
+	XMLName xml.Name `xml:"Number"`
+	Number  string   `xml:",chardata"`
+
+	SendDigits string `xml:"sendDigits,attr,omitempty"`
+	URL        string `xml:"url,attr,omitempty"`
+	Method     string `xml:"method,attr,omitempty"`
 }
 
 // Validate returns an error if the TwiML is constructed improperly
 func (n *Number) Validate() error {
 	ok := Validate(
 		NumericOpt(n.SendDigits),
+		AllowedURL(n.URL),
 		AllowedMethod(n.Method),
 		Required(n.Number),
 	)
@@ -299,7 +429,7 @@ func (n *Number) Validate() error {
 	return nil
 }
 
-// Type returns the XML name of the verb
+// Type returns the name of the verb
 func (n *Number) Type() string {
 	return "Number"
 }
@@ -315,7 +445,7 @@ func (p *Pause) Validate() error {
 	return nil
 }
 
-// Type returns the XML name of the verb
+// Type returns the name of the verb
 func (p *Pause) Type() string {
 	return "Pause"
 }
@@ -323,42 +453,44 @@ func (p *Pause) Type() string {
 // Play TwiML
 type Play struct {
 	XMLName xml.Name `xml:"Play"`
-	Loop    int      `xml:"loop,attr,omitempty"`
-	Digits  string   `xml:"digits,attr,omitempty"`
 	URL     string   `xml:",chardata"`
+
+	Loop   int    `xml:"loop,attr,omitempty"`
+	Digits string `xml:"digits,attr,omitempty"`
 }
 
 // Validate returns an error if the TwiML is constructed improperly
 func (p *Play) Validate() error {
-
 	ok := Validate(
 		NumericOrWait(p.Digits),
+		AllowedURL(p.URL),
 	)
-
 	if !ok {
 		return fmt.Errorf("%s markup failed validation", p.Type())
 	}
 	return nil
 }
 
-// Type returns the XML name of the verb
+// Type returns the name of the verb
 func (p *Play) Type() string {
 	return "Play"
 }
 
 // Queue TwiML
 type Queue struct {
-	XMLName             xml.Name `xml:"Queue"`
-	URL                 string   `xml:"url,attr,omitempty"`
-	Method              string   `xml:"method,attr,omitempty"`
-	ReservationSid      string   `xml:"reservationSid,attr,omitempty"`
-	PostWorkActivitySid string   `xml:"postWorkActivitySid,attr,omitempty"`
-	Name                string   `xml:",chardata"`
+	XMLName xml.Name `xml:"Queue"`
+	Name    string   `xml:",chardata"`
+
+	URL                 string `xml:"url,attr,omitempty"`
+	Method              string `xml:"method,attr,omitempty"`
+	ReservationSid      string `xml:"reservationSid,attr,omitempty"`
+	PostWorkActivitySid string `xml:"postWorkActivitySid,attr,omitempty"`
 }
 
 // Validate returns an error if the TwiML is constructed improperly
 func (q *Queue) Validate() error {
 	ok := Validate(
+		AllowedURL(q.URL),
 		AllowedMethod(q.Method),
 		Required(q.Name),
 	)
@@ -368,7 +500,7 @@ func (q *Queue) Validate() error {
 	return nil
 }
 
-// Type returns the XML name of the verb
+// Type returns the name of the verb
 func (q *Queue) Type() string {
 	return "Queue"
 }
@@ -392,9 +524,12 @@ type Record struct {
 // Validate returns an error if the TwiML is constructed improperly
 func (r *Record) Validate() error {
 	ok := Validate(
+		AllowedURL(r.Action),
 		AllowedMethod(r.Method),
-		OneOfOpt(r.Trim, TrimSilence, DoNotTrim),
+		OneOfOpt(r.Trim, "trim-silence", "do-not-trim"),
+		AllowedURL(r.RecordingStatusCallback),
 		AllowedMethod(r.RecordingStatusCallbackMethod),
+		AllowedURL(r.TranscribeCallback),
 	)
 	if !ok {
 		return fmt.Errorf("%s markup failed validation", r.Type())
@@ -402,7 +537,7 @@ func (r *Record) Validate() error {
 	return nil
 }
 
-// Type returns the XML name of the verb
+// Type returns the name of the verb
 func (r *Record) Type() string {
 	return "Record"
 }
@@ -410,14 +545,16 @@ func (r *Record) Type() string {
 // Redirect TwiML
 type Redirect struct {
 	XMLName xml.Name `xml:"Redirect"`
-	Method  string   `xml:"method,attr,omitempty"`
 	URL     string   `xml:",chardata"`
+
+	Method string `xml:"method,attr,omitempty"`
 }
 
 // Validate returns an error if the TwiML is constructed improperly
 func (r *Redirect) Validate() error {
 	ok := Validate(
 		AllowedMethod(r.Method),
+		AllowedURL(r.URL),
 		Required(r.URL),
 	)
 	if !ok {
@@ -426,7 +563,7 @@ func (r *Redirect) Validate() error {
 	return nil
 }
 
-// Type returns the XML name of the verb
+// Type returns the name of the verb
 func (r *Redirect) Type() string {
 	return "Redirect"
 }
@@ -448,62 +585,61 @@ func (r *Reject) Validate() error {
 	return nil
 }
 
-// Type returns the XML name of the verb
+// Type returns the name of the verb
 func (r *Reject) Type() string {
 	return "Reject"
 }
 
 // Say TwiML
 type Say struct {
-	XMLName  xml.Name `xml:"Say"`
-	Voice    string   `xml:"voice,attr,omitempty"`
-	Language string   `xml:"language,attr,omitempty"`
-	Loop     int      `xml:"loop,attr,omitempty"`
-	Text     string   `xml:",chardata"`
+	XMLName xml.Name `xml:"Say"`
+	Text    string   `xml:",chardata"`
+
+	Voice    string `xml:"voice,attr,omitempty"`
+	Language string `xml:"language,attr,omitempty"`
+	Loop     int    `xml:"loop,attr,omitempty"`
 }
 
 // Validate returns an error if the TwiML is constructed improperly
 func (s *Say) Validate() error {
 	ok := Validate(
-		OneOfOpt(s.Voice, Man, Woman, Alice),
-		AllowedLanguage(s.Voice, s.Language),
+		OneOfOpt(s.Voice, "man", "woman", "alice"),
 		Required(s.Text),
+		AllowedLanguage(s.Voice, s.Language),
 	)
 	if !ok {
 		return fmt.Errorf("%s markup failed validation", s.Type())
 	}
 	return nil
-
 }
 
-// Type returns the XML name of the verb
+// Type returns the name of the verb
 func (s *Say) Type() string {
 	return "Say"
 }
 
 // Sip TwiML
 type Sip struct {
-	XMLName              xml.Name `xml:"Sip"`
-	Username             string   `xml:"username,attr,omitempty"`
-	Password             string   `xml:"password,attr,omitempty"`
-	URL                  string   `xml:"url,attr,omitempty"`
-	Method               string   `xml:"method,attr,omitempty"`
-	StatusCallbackEvent  string   `xml:"statusCallbackEvent,attr,omitempty"`
-	StatusCallback       string   `xml:"statusCallback,attr,omitempty"`
-	StatusCallbackMethod string   `xml:"statusCallbackMethod,attr,omitempty"`
-	Address              string   `xml:",chardata"`
-}
+	XMLName xml.Name `xml:"Sip"`
+	Address string   `xml:",chardata"`
 
-// TODO: Needs helpers to construct the SIP URL (specifying transport
-// and headers) See https://www.twilio.com/docs/api/twiml/sip
+	Username             string `xml:"username,attr,omitempty"`
+	Password             string `xml:"password,attr,omitempty"`
+	URL                  string `xml:"url,attr,omitempty"`
+	Method               string `xml:"method,attr,omitempty"`
+	StatusCallbackEvent  string `xml:"statusCallbackEvent,attr,omitempty"`
+	StatusCallback       string `xml:"statusCallback,attr,omitempty"`
+	StatusCallbackMethod string `xml:"statusCallbackMethod,attr,omitempty"`
+}
 
 // Validate returns an error if the TwiML is constructed improperly
 func (s *Sip) Validate() error {
-	//TODO: needs a custom validator type for statusCallbackEvent when set
-	//because valid values can be concatenated
 	ok := Validate(
-		AllowedMethod(s.StatusCallbackMethod),
+		AllowedURL(s.URL),
+		AllowedMethod(s.Method),
 		AllowedCallbackEvent(s.StatusCallbackEvent, SipCallbackEvents),
+		AllowedURL(s.StatusCallback),
+		AllowedMethod(s.StatusCallbackMethod),
 		Required(s.Address),
 	)
 	if !ok {
@@ -512,7 +648,7 @@ func (s *Sip) Validate() error {
 	return nil
 }
 
-// Type returns the XML name of the verb
+// Type returns the name of the verb
 func (s *Sip) Type() string {
 	return "Sip"
 }
@@ -531,29 +667,44 @@ type Gather struct {
 	Language              string   `xml:"language,attr,omitempty"`
 	ProfanityFilter       bool     `xml:"profanityFilter,attr,omitempty"`
 	SpeechTimeout         int      `xml:"speechTimeout,attr,omitempty"`
-	Children              []Markup `valid:"-"`
+	Children              []Markup `xml:",omitempty"`
+}
+
+// Add nests markup in the Gather.  Valid children: Say, Play, Pause
+func (g *Gather) Add(ml ...Markup) {
+	g.Children = append(g.Children, ml...)
+}
+
+// Kids returns the markup nested in the Gather
+func (g *Gather) Kids() []Markup {
+	return g.Children
+}
+
+// SetKids replaces the markup nested in the Gather
+func (g *Gather) SetKids(ml []Markup) {
+	g.Children = ml
 }
 
 // Validate returns an error if the TwiML is constructed improperly
 func (g *Gather) Validate() error {
 	var errs []error
-
-	for _, s := range g.Children {
-		switch t := s.Type(); t {
-		default:
-			return fmt.Errorf("Not a valid verb as child of Gather: '%T'", s)
+	for _, m := range g.Children {
+		switch m.Type() {
 		case "Say", "Play", "Pause":
-			if childErr := s.Validate(); childErr != nil {
-				errs = append(errs, childErr)
+			if err := m.Validate(); err != nil {
+				errs = append(errs, err)
 			}
+		default:
+			return fmt.Errorf("Not a valid verb as child of %s: '%T'", g.Type(), m)
 		}
 	}
 	ok := Validate(
+		AllowedURL(g.Action),
 		AllowedMethod(g.Method),
+		AllowedURL(g.PartialResultCallback),
 	)
 	if !ok {
-		errs = append(errs, fmt.Errorf("Gather failed validation"))
-		return ValidationError{errs}
+		errs = append(errs, fmt.Errorf("%s markup failed validation", g.Type()))
 	}
 	if len(errs) > 0 {
 		return ValidationError{errs}
@@ -561,16 +712,7 @@ func (g *Gather) Validate() error {
 	return nil
 }
 
-// Add collects digits a caller enter by pressing the keypad to an existing Gather verb.
-// Valid nested verbs: Say, Pause, Play
-func (g *Gather) Add(ml ...Markup) {
-	for _, s := range ml {
-		g.Children = append(g.Children, s)
-	}
-	return
-}
-
-// Type returns the XML name of the verb
+// Type returns the name of the verb
 func (g *Gather) Type() string {
 	return "Gather"
 }
@@ -0,0 +1,675 @@
+// Code generated by twimlgen from vocabulary.yaml. DO NOT EDIT.
+
+package twiml
+
+func (c *Client) writeTwiML(w *twimlWriter) {
+	w.start("Client")
+	w.attr("method", c.Method)
+	w.attr("url", c.URL)
+	w.attr("statusCallback", c.StatusCallback)
+	w.attr("statusCallbackEvent", c.StatusCallbackEvent)
+	w.attr("statusCallbackMethod", c.StatusCallbackMethod)
+	w.closeStart()
+	w.text(c.Name)
+	w.textElement("Identity", c.Identity)
+	w.children(c.Children)
+	w.end("Client")
+}
+
+// urls returns the fields of the verb holding webhook URLs
+func (c *Client) urls() []*string {
+	return []*string{&c.URL, &c.StatusCallback}
+}
+
+// MarshalJSON encodes the verb as JSON
+func (c Client) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&c)
+}
+
+// UnmarshalJSON decodes the verb from JSON
+func (c *Client) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(c, b)
+}
+
+// MarshalYAML encodes the verb as YAML
+func (c Client) MarshalYAML() (interface{}, error) {
+	return marshalYAML(&c)
+}
+
+// UnmarshalYAML decodes the verb from YAML
+func (c *Client) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(c, unmarshal)
+}
+
+func (p Parameter) writeTwiML(w *twimlWriter) {
+	w.start("Parameter")
+	w.attr("name", p.Name)
+	w.attr("value", p.Value)
+	w.closeStart()
+	w.end("Parameter")
+}
+
+// MarshalJSON encodes the verb as JSON
+func (p Parameter) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&p)
+}
+
+// UnmarshalJSON decodes the verb from JSON
+func (p *Parameter) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(p, b)
+}
+
+// MarshalYAML encodes the verb as YAML
+func (p Parameter) MarshalYAML() (interface{}, error) {
+	return marshalYAML(&p)
+}
+
+// UnmarshalYAML decodes the verb from YAML
+func (p *Parameter) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(p, unmarshal)
+}
+
+func (c *Conference) writeTwiML(w *twimlWriter) {
+	w.start("Conference")
+	w.attrBool("muted", c.Muted)
+	w.attr("beep", c.Beep)
+	w.attrBool("startConferenceOnEnter", c.StartConferenceOnEnter)
+	w.attrBool("endConferenceOnExit", c.EndConferenceOnExit)
+	w.attr("waitUrl", c.WaitURL)
+	w.attr("waitMethod", c.WaitMethod)
+	w.attrInt("maxParticipants", c.MaxParticipants)
+	w.attr("record", c.Record)
+	w.attr("region", c.Region)
+	w.attr("trim", c.Trim)
+	w.attr("coach", c.Coach)
+	w.attr("statusCallbackEvent", c.StatusCallbackEvent)
+	w.attr("statusCallback", c.StatusCallback)
+	w.attr("statusCallbackMethod", c.StatusCallbackMethod)
+	w.attr("recordingStatusCallback", c.RecordingStatusCallback)
+	w.attr("recordingStatusCallbackMethod", c.RecordingStatusCallbackMethod)
+	w.attr("recordingStatusCallbackEvent", c.RecordingStatusCallbackEvent)
+	w.attr("eventCallbackUrl", c.EventCallbackURL)
+	w.closeStart()
+	w.text(c.ConferenceName)
+	w.end("Conference")
+}
+
+// urls returns the fields of the verb holding webhook URLs
+func (c *Conference) urls() []*string {
+	return []*string{&c.WaitURL, &c.StatusCallback, &c.RecordingStatusCallback, &c.EventCallbackURL}
+}
+
+// MarshalJSON encodes the verb as JSON
+func (c Conference) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&c)
+}
+
+// UnmarshalJSON decodes the verb from JSON
+func (c *Conference) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(c, b)
+}
+
+// MarshalYAML encodes the verb as YAML
+func (c Conference) MarshalYAML() (interface{}, error) {
+	return marshalYAML(&c)
+}
+
+// UnmarshalYAML decodes the verb from YAML
+func (c *Conference) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(c, unmarshal)
+}
+
+func (d *Dial) writeTwiML(w *twimlWriter) {
+	w.start("Dial")
+	w.attr("action", d.Action)
+	w.attrBool("answerOnBridge", d.AnswerOnBridge)
+	w.attr("callerId", d.CallerID)
+	w.attrBool("hangupOnStar", d.HangupOnStar)
+	w.attr("method", d.Method)
+	w.attr("record", d.Record)
+	w.attr("recordingStatusCallback", d.RecordingStatusCallback)
+	w.attr("recordingStatusCallbackMethod", d.RecordingStatusCallbackMethod)
+	w.attr("recordingStatusCallbackEvent", d.RecordingStatusCallbackEvent)
+	w.attr("recordingTrack", d.RecordingTrack)
+	w.attr("referUrl", d.ReferURL)
+	w.attr("referMethod", d.ReferMethod)
+	w.attr("ringTone", d.RingTone)
+	w.attrBool("sequential", d.Sequential)
+	w.attrInt("timeout", d.Timeout)
+	w.attrInt("timeLimit", d.TimeLimit)
+	w.attr("trim", d.Trim)
+	w.closeStart()
+	w.text(d.Number)
+	w.children(d.Children)
+	w.end("Dial")
+}
+
+// urls returns the fields of the verb holding webhook URLs
+func (d *Dial) urls() []*string {
+	return []*string{&d.Action, &d.RecordingStatusCallback, &d.ReferURL}
+}
+
+// MarshalJSON encodes the verb as JSON
+func (d Dial) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&d)
+}
+
+// UnmarshalJSON decodes the verb from JSON
+func (d *Dial) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(d, b)
+}
+
+// MarshalYAML encodes the verb as YAML
+func (d Dial) MarshalYAML() (interface{}, error) {
+	return marshalYAML(&d)
+}
+
+// UnmarshalYAML decodes the verb from YAML
+func (d *Dial) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(d, unmarshal)
+}
+
+func (e *Enqueue) writeTwiML(w *twimlWriter) {
+	w.start("Enqueue")
+	w.attr("action", e.Action)
+	w.attr("method", e.Method)
+	w.attr("waitUrl", e.WaitURL)
+	w.attr("waitUrlMethod", e.WaitURLMethod)
+	w.attr("workflowSid", e.WorkflowSid)
+	w.closeStart()
+	w.text(e.QueueName)
+	w.end("Enqueue")
+}
+
+// urls returns the fields of the verb holding webhook URLs
+func (e *Enqueue) urls() []*string {
+	return []*string{&e.Action, &e.WaitURL}
+}
+
+// MarshalJSON encodes the verb as JSON
+func (e Enqueue) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&e)
+}
+
+// UnmarshalJSON decodes the verb from JSON
+func (e *Enqueue) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(e, b)
+}
+
+// MarshalYAML encodes the verb as YAML
+func (e Enqueue) MarshalYAML() (interface{}, error) {
+	return marshalYAML(&e)
+}
+
+// UnmarshalYAML decodes the verb from YAML
+func (e *Enqueue) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(e, unmarshal)
+}
+
+func (h *Hangup) writeTwiML(w *twimlWriter) {
+	w.start("Hangup")
+	w.closeStart()
+	w.end("Hangup")
+}
+
+// MarshalJSON encodes the verb as JSON
+func (h Hangup) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&h)
+}
+
+// UnmarshalJSON decodes the verb from JSON
+func (h *Hangup) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(h, b)
+}
+
+// MarshalYAML encodes the verb as YAML
+func (h Hangup) MarshalYAML() (interface{}, error) {
+	return marshalYAML(&h)
+}
+
+// UnmarshalYAML decodes the verb from YAML
+func (h *Hangup) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(h, unmarshal)
+}
+
+func (l *Leave) writeTwiML(w *twimlWriter) {
+	w.start("Leave")
+	w.closeStart()
+	w.end("Leave")
+}
+
+// MarshalJSON encodes the verb as JSON
+func (l Leave) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&l)
+}
+
+// UnmarshalJSON decodes the verb from JSON
+func (l *Leave) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(l, b)
+}
+
+// MarshalYAML encodes the verb as YAML
+func (l Leave) MarshalYAML() (interface{}, error) {
+	return marshalYAML(&l)
+}
+
+// UnmarshalYAML decodes the verb from YAML
+func (l *Leave) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(l, unmarshal)
+}
+
+func (s *Sms) writeTwiML(w *twimlWriter) {
+	w.start("Message")
+	w.attr("to", s.To)
+	w.attr("from", s.From)
+	w.attr("action", s.Action)
+	w.attr("method", s.Method)
+	w.attr("statusCallback", s.StatusCallback)
+	w.closeStart()
+	w.text(s.Text)
+	w.children(s.Children)
+	w.end("Message")
+}
+
+// urls returns the fields of the verb holding webhook URLs
+func (s *Sms) urls() []*string {
+	return []*string{&s.Action, &s.StatusCallback}
+}
+
+// MarshalJSON encodes the verb as JSON
+func (s Sms) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&s)
+}
+
+// UnmarshalJSON decodes the verb from JSON
+func (s *Sms) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(s, b)
+}
+
+// MarshalYAML encodes the verb as YAML
+func (s Sms) MarshalYAML() (interface{}, error) {
+	return marshalYAML(&s)
+}
+
+// UnmarshalYAML decodes the verb from YAML
+func (s *Sms) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(s, unmarshal)
+}
+
+func (m *Media) writeTwiML(w *twimlWriter) {
+	w.start("Media")
+	w.closeStart()
+	w.text(m.URL)
+	w.end("Media")
+}
+
+// urls returns the fields of the verb holding webhook URLs
+func (m *Media) urls() []*string {
+	return []*string{&m.URL}
+}
+
+// MarshalJSON encodes the verb as JSON
+func (m Media) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&m)
+}
+
+// UnmarshalJSON decodes the verb from JSON
+func (m *Media) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(m, b)
+}
+
+// MarshalYAML encodes the verb as YAML
+func (m Media) MarshalYAML() (interface{}, error) {
+	return marshalYAML(&m)
+}
+
+// UnmarshalYAML decodes the verb from YAML
+func (m *Media) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(m, unmarshal)
+}
+
+func (n *Number) writeTwiML(w *twimlWriter) {
+	w.start("Number")
+	w.attr("sendDigits", n.SendDigits)
+	w.attr("url", n.URL)
+	w.attr("method", n.Method)
+	w.closeStart()
+	w.text(n.Number)
+	w.end("Number")
+}
+
+// urls returns the fields of the verb holding webhook URLs
+func (n *Number) urls() []*string {
+	return []*string{&n.URL}
+}
+
+// MarshalJSON encodes the verb as JSON
+func (n Number) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&n)
+}
+
+// UnmarshalJSON decodes the verb from JSON
+func (n *Number) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(n, b)
+}
+
+// MarshalYAML encodes the verb as YAML
+func (n Number) MarshalYAML() (interface{}, error) {
+	return marshalYAML(&n)
+}
+
+// UnmarshalYAML decodes the verb from YAML
+func (n *Number) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(n, unmarshal)
+}
+
+func (p *Pause) writeTwiML(w *twimlWriter) {
+	w.start("Pause")
+	w.attrInt("length", p.Length)
+	w.closeStart()
+	w.end("Pause")
+}
+
+// MarshalJSON encodes the verb as JSON
+func (p Pause) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&p)
+}
+
+// UnmarshalJSON decodes the verb from JSON
+func (p *Pause) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(p, b)
+}
+
+// MarshalYAML encodes the verb as YAML
+func (p Pause) MarshalYAML() (interface{}, error) {
+	return marshalYAML(&p)
+}
+
+// UnmarshalYAML decodes the verb from YAML
+func (p *Pause) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(p, unmarshal)
+}
+
+func (p *Play) writeTwiML(w *twimlWriter) {
+	w.start("Play")
+	w.attrInt("loop", p.Loop)
+	w.attr("digits", p.Digits)
+	w.closeStart()
+	w.text(p.URL)
+	w.end("Play")
+}
+
+// urls returns the fields of the verb holding webhook URLs
+func (p *Play) urls() []*string {
+	return []*string{&p.URL}
+}
+
+// MarshalJSON encodes the verb as JSON
+func (p Play) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&p)
+}
+
+// UnmarshalJSON decodes the verb from JSON
+func (p *Play) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(p, b)
+}
+
+// MarshalYAML encodes the verb as YAML
+func (p Play) MarshalYAML() (interface{}, error) {
+	return marshalYAML(&p)
+}
+
+// UnmarshalYAML decodes the verb from YAML
+func (p *Play) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(p, unmarshal)
+}
+
+func (q *Queue) writeTwiML(w *twimlWriter) {
+	w.start("Queue")
+	w.attr("url", q.URL)
+	w.attr("method", q.Method)
+	w.attr("reservationSid", q.ReservationSid)
+	w.attr("postWorkActivitySid", q.PostWorkActivitySid)
+	w.closeStart()
+	w.text(q.Name)
+	w.end("Queue")
+}
+
+// urls returns the fields of the verb holding webhook URLs
+func (q *Queue) urls() []*string {
+	return []*string{&q.URL}
+}
+
+// MarshalJSON encodes the verb as JSON
+func (q Queue) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&q)
+}
+
+// UnmarshalJSON decodes the verb from JSON
+func (q *Queue) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(q, b)
+}
+
+// MarshalYAML encodes the verb as YAML
+func (q Queue) MarshalYAML() (interface{}, error) {
+	return marshalYAML(&q)
+}
+
+// UnmarshalYAML decodes the verb from YAML
+func (q *Queue) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(q, unmarshal)
+}
+
+func (r *Record) writeTwiML(w *twimlWriter) {
+	w.start("Record")
+	w.attr("action", r.Action)
+	w.attr("method", r.Method)
+	w.attrInt("timeout", r.Timeout)
+	w.attr("finishOnKey", r.FinishOnKey)
+	w.attrInt("maxLength", r.MaxLength)
+	w.attrBool("playBeep", r.PlayBeep)
+	w.attr("trim", r.Trim)
+	w.attr("recordingStatusCallback", r.RecordingStatusCallback)
+	w.attr("recordingStatusCallbackMethod", r.RecordingStatusCallbackMethod)
+	w.attrBool("transcribe", r.Transcribe)
+	w.attr("transcribeCallback", r.TranscribeCallback)
+	w.closeStart()
+	w.end("Record")
+}
+
+// urls returns the fields of the verb holding webhook URLs
+func (r *Record) urls() []*string {
+	return []*string{&r.Action, &r.RecordingStatusCallback, &r.TranscribeCallback}
+}
+
+// MarshalJSON encodes the verb as JSON
+func (r Record) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&r)
+}
+
+// UnmarshalJSON decodes the verb from JSON
+func (r *Record) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(r, b)
+}
+
+// MarshalYAML encodes the verb as YAML
+func (r Record) MarshalYAML() (interface{}, error) {
+	return marshalYAML(&r)
+}
+
+// UnmarshalYAML decodes the verb from YAML
+func (r *Record) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(r, unmarshal)
+}
+
+func (r *Redirect) writeTwiML(w *twimlWriter) {
+	w.start("Redirect")
+	w.attr("method", r.Method)
+	w.closeStart()
+	w.text(r.URL)
+	w.end("Redirect")
+}
+
+// urls returns the fields of the verb holding webhook URLs
+func (r *Redirect) urls() []*string {
+	return []*string{&r.URL}
+}
+
+// MarshalJSON encodes the verb as JSON
+func (r Redirect) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&r)
+}
+
+// UnmarshalJSON decodes the verb from JSON
+func (r *Redirect) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(r, b)
+}
+
+// MarshalYAML encodes the verb as YAML
+func (r Redirect) MarshalYAML() (interface{}, error) {
+	return marshalYAML(&r)
+}
+
+// UnmarshalYAML decodes the verb from YAML
+func (r *Redirect) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(r, unmarshal)
+}
+
+func (r *Reject) writeTwiML(w *twimlWriter) {
+	w.start("Reject")
+	w.attr("reason", r.Reason)
+	w.closeStart()
+	w.end("Reject")
+}
+
+// MarshalJSON encodes the verb as JSON
+func (r Reject) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&r)
+}
+
+// UnmarshalJSON decodes the verb from JSON
+func (r *Reject) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(r, b)
+}
+
+// MarshalYAML encodes the verb as YAML
+func (r Reject) MarshalYAML() (interface{}, error) {
+	return marshalYAML(&r)
+}
+
+// UnmarshalYAML decodes the verb from YAML
+func (r *Reject) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(r, unmarshal)
+}
+
+func (s *Say) writeTwiML(w *twimlWriter) {
+	w.start("Say")
+	w.attr("voice", s.Voice)
+	w.attr("language", s.Language)
+	w.attrInt("loop", s.Loop)
+	w.closeStart()
+	w.text(s.Text)
+	w.end("Say")
+}
+
+// MarshalJSON encodes the verb as JSON
+func (s Say) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&s)
+}
+
+// UnmarshalJSON decodes the verb from JSON
+func (s *Say) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(s, b)
+}
+
+// MarshalYAML encodes the verb as YAML
+func (s Say) MarshalYAML() (interface{}, error) {
+	return marshalYAML(&s)
+}
+
+// UnmarshalYAML decodes the verb from YAML
+func (s *Say) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(s, unmarshal)
+}
+
+func (s *Sip) writeTwiML(w *twimlWriter) {
+	w.start("Sip")
+	w.attr("username", s.Username)
+	w.attr("password", s.Password)
+	w.attr("url", s.URL)
+	w.attr("method", s.Method)
+	w.attr("statusCallbackEvent", s.StatusCallbackEvent)
+	w.attr("statusCallback", s.StatusCallback)
+	w.attr("statusCallbackMethod", s.StatusCallbackMethod)
+	w.closeStart()
+	w.text(s.Address)
+	w.end("Sip")
+}
+
+// urls returns the fields of the verb holding webhook URLs
+func (s *Sip) urls() []*string {
+	return []*string{&s.URL, &s.StatusCallback}
+}
+
+// MarshalJSON encodes the verb as JSON
+func (s Sip) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&s)
+}
+
+// UnmarshalJSON decodes the verb from JSON
+func (s *Sip) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(s, b)
+}
+
+// MarshalYAML encodes the verb as YAML
+func (s Sip) MarshalYAML() (interface{}, error) {
+	return marshalYAML(&s)
+}
+
+// UnmarshalYAML decodes the verb from YAML
+func (s *Sip) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(s, unmarshal)
+}
+
+func (g *Gather) writeTwiML(w *twimlWriter) {
+	w.start("Gather")
+	w.attr("action", g.Action)
+	w.attr("method", g.Method)
+	w.attrInt("timeout", g.Timeout)
+	w.attr("finishOnKey", g.FinishOnKey)
+	w.attrInt("numDigits", g.NumDigits)
+	w.attr("input", g.Input)
+	w.attr("hints", g.Hints)
+	w.attr("partialResultCallback", g.PartialResultCallback)
+	w.attr("language", g.Language)
+	w.attrBool("profanityFilter", g.ProfanityFilter)
+	w.attrInt("speechTimeout", g.SpeechTimeout)
+	w.closeStart()
+	w.children(g.Children)
+	w.end("Gather")
+}
+
+// urls returns the fields of the verb holding webhook URLs
+func (g *Gather) urls() []*string {
+	return []*string{&g.Action, &g.PartialResultCallback}
+}
+
+// MarshalJSON encodes the verb as JSON
+func (g Gather) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&g)
+}
+
+// UnmarshalJSON decodes the verb from JSON
+func (g *Gather) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(g, b)
+}
+
+// MarshalYAML encodes the verb as YAML
+func (g Gather) MarshalYAML() (interface{}, error) {
+	return marshalYAML(&g)
+}
+
+// UnmarshalYAML decodes the verb from YAML
+func (g *Gather) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(g, unmarshal)
+}
@@ -0,0 +1,876 @@
+// Code generated by twimlgen from vocabulary.yaml. DO NOT EDIT.
+
+package twiml
+
+import (
+	"bytes"
+	"encoding/xml"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generated vocabulary", func() {
+	Context("Client", func() {
+		sample := func() *Client {
+			return &Client{
+				Name:                 "text",
+				Identity:             "Identity",
+				Method:               "POST",
+				URL:                  "url",
+				StatusCallback:       "statusCallback",
+				StatusCallbackEvent:  "statusCallbackEvent",
+				StatusCallbackMethod: "statusCallbackMethod",
+			}
+		}
+		xmlSample := `<Client method="POST" url="url" statusCallback="statusCallback" statusCallbackEvent="statusCallbackEvent" statusCallbackMethod="statusCallbackMethod">text<Identity>Identity</Identity></Client>`
+
+		It("decodes every attribute", func() {
+			r, err := Decode([]byte("<Response>" + xmlSample + "</Response>"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Children).To(HaveLen(1))
+			Expect(r.Children[0]).To(Equal(sample()))
+		})
+
+		It("writes the same XML with and without reflection", func() {
+			b, err := xml.Marshal(sample())
+			Expect(err).ToNot(HaveOccurred())
+			var buf bytes.Buffer
+			sample().writeTwiML(&twimlWriter{buf: &buf})
+			Expect(buf.String()).To(Equal(string(b)))
+			Expect(buf.String()).To(Equal(xmlSample))
+		})
+
+		It("rejects an invalid method", func() {
+			m := sample()
+			m.Method = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid url", func() {
+			m := sample()
+			m.URL = "ftp://example.com/url"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid statusCallback", func() {
+			m := sample()
+			m.StatusCallback = "ftp://example.com/statusCallback"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+	})
+	Context("Parameter", func() {
+		sample := func() *Parameter {
+			return &Parameter{
+				Name:  "name",
+				Value: "value",
+			}
+		}
+		xmlSample := `<Parameter name="name" value="value"></Parameter>`
+
+		It("decodes every attribute", func() {
+			r, err := Decode([]byte("<Response>" + xmlSample + "</Response>"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Children).To(HaveLen(1))
+			Expect(r.Children[0]).To(Equal(sample()))
+		})
+
+		It("writes the same XML with and without reflection", func() {
+			b, err := xml.Marshal(sample())
+			Expect(err).ToNot(HaveOccurred())
+			var buf bytes.Buffer
+			sample().writeTwiML(&twimlWriter{buf: &buf})
+			Expect(buf.String()).To(Equal(string(b)))
+			Expect(buf.String()).To(Equal(xmlSample))
+		})
+	})
+	Context("Conference", func() {
+		sample := func() *Conference {
+			return &Conference{
+				ConferenceName:                "text",
+				Muted:                         true,
+				Beep:                          "true",
+				StartConferenceOnEnter:        true,
+				EndConferenceOnExit:           true,
+				WaitURL:                       "waitUrl",
+				WaitMethod:                    "POST",
+				MaxParticipants:               7,
+				Record:                        "do-not-record",
+				Region:                        "region",
+				Trim:                          "trim-silence",
+				Coach:                         "coach",
+				StatusCallbackEvent:           "statusCallbackEvent",
+				StatusCallback:                "statusCallback",
+				StatusCallbackMethod:          "POST",
+				RecordingStatusCallback:       "recordingStatusCallback",
+				RecordingStatusCallbackMethod: "POST",
+				RecordingStatusCallbackEvent:  "recordingStatusCallbackEvent",
+				EventCallbackURL:              "eventCallbackUrl",
+			}
+		}
+		xmlSample := `<Conference muted="true" beep="true" startConferenceOnEnter="true" endConferenceOnExit="true" waitUrl="waitUrl" waitMethod="POST" maxParticipants="7" record="do-not-record" region="region" trim="trim-silence" coach="coach" statusCallbackEvent="statusCallbackEvent" statusCallback="statusCallback" statusCallbackMethod="POST" recordingStatusCallback="recordingStatusCallback" recordingStatusCallbackMethod="POST" recordingStatusCallbackEvent="recordingStatusCallbackEvent" eventCallbackUrl="eventCallbackUrl">text</Conference>`
+
+		It("decodes every attribute", func() {
+			r, err := Decode([]byte("<Response>" + xmlSample + "</Response>"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Children).To(HaveLen(1))
+			Expect(r.Children[0]).To(Equal(sample()))
+		})
+
+		It("writes the same XML with and without reflection", func() {
+			b, err := xml.Marshal(sample())
+			Expect(err).ToNot(HaveOccurred())
+			var buf bytes.Buffer
+			sample().writeTwiML(&twimlWriter{buf: &buf})
+			Expect(buf.String()).To(Equal(string(b)))
+			Expect(buf.String()).To(Equal(xmlSample))
+		})
+
+		It("rejects an invalid beep", func() {
+			m := sample()
+			m.Beep = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid waitUrl", func() {
+			m := sample()
+			m.WaitURL = "ftp://example.com/waitUrl"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid waitMethod", func() {
+			m := sample()
+			m.WaitMethod = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid record", func() {
+			m := sample()
+			m.Record = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid trim", func() {
+			m := sample()
+			m.Trim = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid statusCallbackEvent", func() {
+			m := sample()
+			m.StatusCallbackEvent = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid statusCallback", func() {
+			m := sample()
+			m.StatusCallback = "ftp://example.com/statusCallback"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid statusCallbackMethod", func() {
+			m := sample()
+			m.StatusCallbackMethod = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid recordingStatusCallback", func() {
+			m := sample()
+			m.RecordingStatusCallback = "ftp://example.com/recordingStatusCallback"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid recordingStatusCallbackMethod", func() {
+			m := sample()
+			m.RecordingStatusCallbackMethod = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid eventCallbackUrl", func() {
+			m := sample()
+			m.EventCallbackURL = "ftp://example.com/eventCallbackUrl"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+	})
+	Context("Dial", func() {
+		sample := func() *Dial {
+			return &Dial{
+				Number:                        "text",
+				Action:                        "action",
+				AnswerOnBridge:                true,
+				CallerID:                      "callerId",
+				HangupOnStar:                  true,
+				Method:                        "POST",
+				Record:                        "record",
+				RecordingStatusCallback:       "recordingStatusCallback",
+				RecordingStatusCallbackMethod: "recordingStatusCallbackMethod",
+				RecordingStatusCallbackEvent:  "recordingStatusCallbackEvent",
+				RecordingTrack:                "both",
+				ReferURL:                      "referUrl",
+				ReferMethod:                   "POST",
+				RingTone:                      "ringTone",
+				Sequential:                    true,
+				Timeout:                       15,
+				TimeLimit:                     16,
+				Trim:                          "trim",
+			}
+		}
+		xmlSample := `<Dial action="action" answerOnBridge="true" callerId="callerId" hangupOnStar="true" method="POST" record="record" recordingStatusCallback="recordingStatusCallback" recordingStatusCallbackMethod="recordingStatusCallbackMethod" recordingStatusCallbackEvent="recordingStatusCallbackEvent" recordingTrack="both" referUrl="referUrl" referMethod="POST" ringTone="ringTone" sequential="true" timeout="15" timeLimit="16" trim="trim">text</Dial>`
+
+		It("decodes every attribute", func() {
+			r, err := Decode([]byte("<Response>" + xmlSample + "</Response>"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Children).To(HaveLen(1))
+			Expect(r.Children[0]).To(Equal(sample()))
+		})
+
+		It("writes the same XML with and without reflection", func() {
+			b, err := xml.Marshal(sample())
+			Expect(err).ToNot(HaveOccurred())
+			var buf bytes.Buffer
+			sample().writeTwiML(&twimlWriter{buf: &buf})
+			Expect(buf.String()).To(Equal(string(b)))
+			Expect(buf.String()).To(Equal(xmlSample))
+		})
+
+		It("rejects an invalid action", func() {
+			m := sample()
+			m.Action = "ftp://example.com/action"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid method", func() {
+			m := sample()
+			m.Method = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid recordingStatusCallback", func() {
+			m := sample()
+			m.RecordingStatusCallback = "ftp://example.com/recordingStatusCallback"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid recordingTrack", func() {
+			m := sample()
+			m.RecordingTrack = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid referUrl", func() {
+			m := sample()
+			m.ReferURL = "ftp://example.com/referUrl"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid referMethod", func() {
+			m := sample()
+			m.ReferMethod = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+	})
+	Context("Enqueue", func() {
+		sample := func() *Enqueue {
+			return &Enqueue{
+				QueueName:     "text",
+				Action:        "action",
+				Method:        "POST",
+				WaitURL:       "waitUrl",
+				WaitURLMethod: "POST",
+				WorkflowSid:   "workflowSid",
+			}
+		}
+		xmlSample := `<Enqueue action="action" method="POST" waitUrl="waitUrl" waitUrlMethod="POST" workflowSid="workflowSid">text</Enqueue>`
+
+		It("decodes every attribute", func() {
+			r, err := Decode([]byte("<Response>" + xmlSample + "</Response>"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Children).To(HaveLen(1))
+			Expect(r.Children[0]).To(Equal(sample()))
+		})
+
+		It("writes the same XML with and without reflection", func() {
+			b, err := xml.Marshal(sample())
+			Expect(err).ToNot(HaveOccurred())
+			var buf bytes.Buffer
+			sample().writeTwiML(&twimlWriter{buf: &buf})
+			Expect(buf.String()).To(Equal(string(b)))
+			Expect(buf.String()).To(Equal(xmlSample))
+		})
+
+		It("rejects an invalid action", func() {
+			m := sample()
+			m.Action = "ftp://example.com/action"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid method", func() {
+			m := sample()
+			m.Method = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid waitUrl", func() {
+			m := sample()
+			m.WaitURL = "ftp://example.com/waitUrl"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid waitUrlMethod", func() {
+			m := sample()
+			m.WaitURLMethod = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+	})
+	Context("Hangup", func() {
+		sample := func() *Hangup {
+			return &Hangup{}
+		}
+		xmlSample := `<Hangup></Hangup>`
+
+		It("decodes every attribute", func() {
+			r, err := Decode([]byte("<Response>" + xmlSample + "</Response>"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Children).To(HaveLen(1))
+			Expect(r.Children[0]).To(Equal(sample()))
+		})
+
+		It("writes the same XML with and without reflection", func() {
+			b, err := xml.Marshal(sample())
+			Expect(err).ToNot(HaveOccurred())
+			var buf bytes.Buffer
+			sample().writeTwiML(&twimlWriter{buf: &buf})
+			Expect(buf.String()).To(Equal(string(b)))
+			Expect(buf.String()).To(Equal(xmlSample))
+		})
+	})
+	Context("Leave", func() {
+		sample := func() *Leave {
+			return &Leave{}
+		}
+		xmlSample := `<Leave></Leave>`
+
+		It("decodes every attribute", func() {
+			r, err := Decode([]byte("<Response>" + xmlSample + "</Response>"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Children).To(HaveLen(1))
+			Expect(r.Children[0]).To(Equal(sample()))
+		})
+
+		It("writes the same XML with and without reflection", func() {
+			b, err := xml.Marshal(sample())
+			Expect(err).ToNot(HaveOccurred())
+			var buf bytes.Buffer
+			sample().writeTwiML(&twimlWriter{buf: &buf})
+			Expect(buf.String()).To(Equal(string(b)))
+			Expect(buf.String()).To(Equal(xmlSample))
+		})
+	})
+	Context("Message", func() {
+		sample := func() *Sms {
+			return &Sms{
+				Text:           "text",
+				To:             "to",
+				From:           "from",
+				Action:         "action",
+				Method:         "POST",
+				StatusCallback: "statusCallback",
+			}
+		}
+		xmlSample := `<Message to="to" from="from" action="action" method="POST" statusCallback="statusCallback">text</Message>`
+
+		It("decodes every attribute", func() {
+			r, err := Decode([]byte("<Response>" + xmlSample + "</Response>"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Children).To(HaveLen(1))
+			Expect(r.Children[0]).To(Equal(sample()))
+		})
+
+		It("writes the same XML with and without reflection", func() {
+			b, err := xml.Marshal(sample())
+			Expect(err).ToNot(HaveOccurred())
+			var buf bytes.Buffer
+			sample().writeTwiML(&twimlWriter{buf: &buf})
+			Expect(buf.String()).To(Equal(string(b)))
+			Expect(buf.String()).To(Equal(xmlSample))
+		})
+
+		It("rejects an invalid action", func() {
+			m := sample()
+			m.Action = "ftp://example.com/action"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid method", func() {
+			m := sample()
+			m.Method = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid statusCallback", func() {
+			m := sample()
+			m.StatusCallback = "ftp://example.com/statusCallback"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+	})
+	Context("Media", func() {
+		sample := func() *Media {
+			return &Media{
+				URL: "text",
+			}
+		}
+		xmlSample := `<Media>text</Media>`
+
+		It("decodes every attribute", func() {
+			r, err := Decode([]byte("<Response>" + xmlSample + "</Response>"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Children).To(HaveLen(1))
+			Expect(r.Children[0]).To(Equal(sample()))
+		})
+
+		It("writes the same XML with and without reflection", func() {
+			b, err := xml.Marshal(sample())
+			Expect(err).ToNot(HaveOccurred())
+			var buf bytes.Buffer
+			sample().writeTwiML(&twimlWriter{buf: &buf})
+			Expect(buf.String()).To(Equal(string(b)))
+			Expect(buf.String()).To(Equal(xmlSample))
+		})
+
+		It("rejects an invalid URL", func() {
+			m := sample()
+			m.URL = "ftp://example.com/text"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+	})
+	Context("Number", func() {
+		sample := func() *Number {
+			return &Number{
+				Number:     "text",
+				SendDigits: "1234",
+				URL:        "url",
+				Method:     "POST",
+			}
+		}
+		xmlSample := `<Number sendDigits="1234" url="url" method="POST">text</Number>`
+
+		It("decodes every attribute", func() {
+			r, err := Decode([]byte("<Response>" + xmlSample + "</Response>"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Children).To(HaveLen(1))
+			Expect(r.Children[0]).To(Equal(sample()))
+		})
+
+		It("writes the same XML with and without reflection", func() {
+			b, err := xml.Marshal(sample())
+			Expect(err).ToNot(HaveOccurred())
+			var buf bytes.Buffer
+			sample().writeTwiML(&twimlWriter{buf: &buf})
+			Expect(buf.String()).To(Equal(string(b)))
+			Expect(buf.String()).To(Equal(xmlSample))
+		})
+
+		It("rejects an invalid sendDigits", func() {
+			m := sample()
+			m.SendDigits = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid url", func() {
+			m := sample()
+			m.URL = "ftp://example.com/url"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid method", func() {
+			m := sample()
+			m.Method = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+	})
+	Context("Pause", func() {
+		sample := func() *Pause {
+			return &Pause{
+				Length: 1,
+			}
+		}
+		xmlSample := `<Pause length="1"></Pause>`
+
+		It("decodes every attribute", func() {
+			r, err := Decode([]byte("<Response>" + xmlSample + "</Response>"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Children).To(HaveLen(1))
+			Expect(r.Children[0]).To(Equal(sample()))
+		})
+
+		It("writes the same XML with and without reflection", func() {
+			b, err := xml.Marshal(sample())
+			Expect(err).ToNot(HaveOccurred())
+			var buf bytes.Buffer
+			sample().writeTwiML(&twimlWriter{buf: &buf})
+			Expect(buf.String()).To(Equal(string(b)))
+			Expect(buf.String()).To(Equal(xmlSample))
+		})
+	})
+	Context("Play", func() {
+		sample := func() *Play {
+			return &Play{
+				URL:    "text",
+				Loop:   1,
+				Digits: "ww1",
+			}
+		}
+		xmlSample := `<Play loop="1" digits="ww1">text</Play>`
+
+		It("decodes every attribute", func() {
+			r, err := Decode([]byte("<Response>" + xmlSample + "</Response>"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Children).To(HaveLen(1))
+			Expect(r.Children[0]).To(Equal(sample()))
+		})
+
+		It("writes the same XML with and without reflection", func() {
+			b, err := xml.Marshal(sample())
+			Expect(err).ToNot(HaveOccurred())
+			var buf bytes.Buffer
+			sample().writeTwiML(&twimlWriter{buf: &buf})
+			Expect(buf.String()).To(Equal(string(b)))
+			Expect(buf.String()).To(Equal(xmlSample))
+		})
+
+		It("rejects an invalid digits", func() {
+			m := sample()
+			m.Digits = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid URL", func() {
+			m := sample()
+			m.URL = "ftp://example.com/text"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+	})
+	Context("Queue", func() {
+		sample := func() *Queue {
+			return &Queue{
+				Name:                "text",
+				URL:                 "url",
+				Method:              "POST",
+				ReservationSid:      "reservationSid",
+				PostWorkActivitySid: "postWorkActivitySid",
+			}
+		}
+		xmlSample := `<Queue url="url" method="POST" reservationSid="reservationSid" postWorkActivitySid="postWorkActivitySid">text</Queue>`
+
+		It("decodes every attribute", func() {
+			r, err := Decode([]byte("<Response>" + xmlSample + "</Response>"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Children).To(HaveLen(1))
+			Expect(r.Children[0]).To(Equal(sample()))
+		})
+
+		It("writes the same XML with and without reflection", func() {
+			b, err := xml.Marshal(sample())
+			Expect(err).ToNot(HaveOccurred())
+			var buf bytes.Buffer
+			sample().writeTwiML(&twimlWriter{buf: &buf})
+			Expect(buf.String()).To(Equal(string(b)))
+			Expect(buf.String()).To(Equal(xmlSample))
+		})
+
+		It("rejects an invalid url", func() {
+			m := sample()
+			m.URL = "ftp://example.com/url"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid method", func() {
+			m := sample()
+			m.Method = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+	})
+	Context("Record", func() {
+		sample := func() *Record {
+			return &Record{
+				Action:                        "action",
+				Method:                        "POST",
+				Timeout:                       3,
+				FinishOnKey:                   "finishOnKey",
+				MaxLength:                     5,
+				PlayBeep:                      true,
+				Trim:                          "trim-silence",
+				RecordingStatusCallback:       "recordingStatusCallback",
+				RecordingStatusCallbackMethod: "POST",
+				Transcribe:                    true,
+				TranscribeCallback:            "transcribeCallback",
+			}
+		}
+		xmlSample := `<Record action="action" method="POST" timeout="3" finishOnKey="finishOnKey" maxLength="5" playBeep="true" trim="trim-silence" recordingStatusCallback="recordingStatusCallback" recordingStatusCallbackMethod="POST" transcribe="true" transcribeCallback="transcribeCallback"></Record>`
+
+		It("decodes every attribute", func() {
+			r, err := Decode([]byte("<Response>" + xmlSample + "</Response>"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Children).To(HaveLen(1))
+			Expect(r.Children[0]).To(Equal(sample()))
+		})
+
+		It("writes the same XML with and without reflection", func() {
+			b, err := xml.Marshal(sample())
+			Expect(err).ToNot(HaveOccurred())
+			var buf bytes.Buffer
+			sample().writeTwiML(&twimlWriter{buf: &buf})
+			Expect(buf.String()).To(Equal(string(b)))
+			Expect(buf.String()).To(Equal(xmlSample))
+		})
+
+		It("rejects an invalid action", func() {
+			m := sample()
+			m.Action = "ftp://example.com/action"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid method", func() {
+			m := sample()
+			m.Method = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid trim", func() {
+			m := sample()
+			m.Trim = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid recordingStatusCallback", func() {
+			m := sample()
+			m.RecordingStatusCallback = "ftp://example.com/recordingStatusCallback"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid recordingStatusCallbackMethod", func() {
+			m := sample()
+			m.RecordingStatusCallbackMethod = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid transcribeCallback", func() {
+			m := sample()
+			m.TranscribeCallback = "ftp://example.com/transcribeCallback"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+	})
+	Context("Redirect", func() {
+		sample := func() *Redirect {
+			return &Redirect{
+				URL:    "text",
+				Method: "POST",
+			}
+		}
+		xmlSample := `<Redirect method="POST">text</Redirect>`
+
+		It("decodes every attribute", func() {
+			r, err := Decode([]byte("<Response>" + xmlSample + "</Response>"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Children).To(HaveLen(1))
+			Expect(r.Children[0]).To(Equal(sample()))
+		})
+
+		It("writes the same XML with and without reflection", func() {
+			b, err := xml.Marshal(sample())
+			Expect(err).ToNot(HaveOccurred())
+			var buf bytes.Buffer
+			sample().writeTwiML(&twimlWriter{buf: &buf})
+			Expect(buf.String()).To(Equal(string(b)))
+			Expect(buf.String()).To(Equal(xmlSample))
+		})
+
+		It("rejects an invalid method", func() {
+			m := sample()
+			m.Method = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid URL", func() {
+			m := sample()
+			m.URL = "ftp://example.com/text"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+	})
+	Context("Reject", func() {
+		sample := func() *Reject {
+			return &Reject{
+				Reason: "rejected",
+			}
+		}
+		xmlSample := `<Reject reason="rejected"></Reject>`
+
+		It("decodes every attribute", func() {
+			r, err := Decode([]byte("<Response>" + xmlSample + "</Response>"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Children).To(HaveLen(1))
+			Expect(r.Children[0]).To(Equal(sample()))
+		})
+
+		It("writes the same XML with and without reflection", func() {
+			b, err := xml.Marshal(sample())
+			Expect(err).ToNot(HaveOccurred())
+			var buf bytes.Buffer
+			sample().writeTwiML(&twimlWriter{buf: &buf})
+			Expect(buf.String()).To(Equal(string(b)))
+			Expect(buf.String()).To(Equal(xmlSample))
+		})
+
+		It("rejects an invalid reason", func() {
+			m := sample()
+			m.Reason = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+	})
+	Context("Say", func() {
+		sample := func() *Say {
+			return &Say{
+				Text:     "text",
+				Voice:    "man",
+				Language: "language",
+				Loop:     3,
+			}
+		}
+		xmlSample := `<Say voice="man" language="language" loop="3">text</Say>`
+
+		It("decodes every attribute", func() {
+			r, err := Decode([]byte("<Response>" + xmlSample + "</Response>"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Children).To(HaveLen(1))
+			Expect(r.Children[0]).To(Equal(sample()))
+		})
+
+		It("writes the same XML with and without reflection", func() {
+			b, err := xml.Marshal(sample())
+			Expect(err).ToNot(HaveOccurred())
+			var buf bytes.Buffer
+			sample().writeTwiML(&twimlWriter{buf: &buf})
+			Expect(buf.String()).To(Equal(string(b)))
+			Expect(buf.String()).To(Equal(xmlSample))
+		})
+
+		It("rejects an invalid voice", func() {
+			m := sample()
+			m.Voice = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+	})
+	Context("Sip", func() {
+		sample := func() *Sip {
+			return &Sip{
+				Address:              "text",
+				Username:             "username",
+				Password:             "password",
+				URL:                  "url",
+				Method:               "POST",
+				StatusCallbackEvent:  "statusCallbackEvent",
+				StatusCallback:       "statusCallback",
+				StatusCallbackMethod: "POST",
+			}
+		}
+		xmlSample := `<Sip username="username" password="password" url="url" method="POST" statusCallbackEvent="statusCallbackEvent" statusCallback="statusCallback" statusCallbackMethod="POST">text</Sip>`
+
+		It("decodes every attribute", func() {
+			r, err := Decode([]byte("<Response>" + xmlSample + "</Response>"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Children).To(HaveLen(1))
+			Expect(r.Children[0]).To(Equal(sample()))
+		})
+
+		It("writes the same XML with and without reflection", func() {
+			b, err := xml.Marshal(sample())
+			Expect(err).ToNot(HaveOccurred())
+			var buf bytes.Buffer
+			sample().writeTwiML(&twimlWriter{buf: &buf})
+			Expect(buf.String()).To(Equal(string(b)))
+			Expect(buf.String()).To(Equal(xmlSample))
+		})
+
+		It("rejects an invalid url", func() {
+			m := sample()
+			m.URL = "ftp://example.com/url"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid method", func() {
+			m := sample()
+			m.Method = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid statusCallbackEvent", func() {
+			m := sample()
+			m.StatusCallbackEvent = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid statusCallback", func() {
+			m := sample()
+			m.StatusCallback = "ftp://example.com/statusCallback"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid statusCallbackMethod", func() {
+			m := sample()
+			m.StatusCallbackMethod = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+	})
+	Context("Gather", func() {
+		sample := func() *Gather {
+			return &Gather{
+				Action:                "action",
+				Method:                "POST",
+				Timeout:               3,
+				FinishOnKey:           "finishOnKey",
+				NumDigits:             5,
+				Input:                 "input",
+				Hints:                 "hints",
+				PartialResultCallback: "partialResultCallback",
+				Language:              "language",
+				ProfanityFilter:       true,
+				SpeechTimeout:         11,
+			}
+		}
+		xmlSample := `<Gather action="action" method="POST" timeout="3" finishOnKey="finishOnKey" numDigits="5" input="input" hints="hints" partialResultCallback="partialResultCallback" language="language" profanityFilter="true" speechTimeout="11"></Gather>`
+
+		It("decodes every attribute", func() {
+			r, err := Decode([]byte("<Response>" + xmlSample + "</Response>"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Children).To(HaveLen(1))
+			Expect(r.Children[0]).To(Equal(sample()))
+		})
+
+		It("writes the same XML with and without reflection", func() {
+			b, err := xml.Marshal(sample())
+			Expect(err).ToNot(HaveOccurred())
+			var buf bytes.Buffer
+			sample().writeTwiML(&twimlWriter{buf: &buf})
+			Expect(buf.String()).To(Equal(string(b)))
+			Expect(buf.String()).To(Equal(xmlSample))
+		})
+
+		It("rejects an invalid action", func() {
+			m := sample()
+			m.Action = "ftp://example.com/action"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid method", func() {
+			m := sample()
+			m.Method = "invalid value"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an invalid partialResultCallback", func() {
+			m := sample()
+			m.PartialResultCallback = "ftp://example.com/partialResultCallback"
+			Expect(m.Validate()).ToNot(Succeed())
+		})
+	})
+})
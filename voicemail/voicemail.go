@@ -0,0 +1,215 @@
+// Package voicemail records messages from callers.  A Voicemail plays a greeting, records
+// the caller with the Record verb and passes the message to a Sink as the action, recording
+// status and transcription callbacks arrive from Twilio.
+//
+//	vm := voicemail.New("/voicemail", sink)
+//	vm.Greeting = []twiml.Markup{&twiml.Say{Text: "Leave a message for Alice after the beep"}}
+//	http.Handle("/voicemail/", vm)
+package voicemail
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/BTBurke/twiml"
+)
+
+// Default settings of the recording returned by New
+const (
+	DefaultMaxLength   = 120
+	DefaultFinishOnKey = "#"
+)
+
+// Message is a voicemail left by a caller.  Each callback fills in the fields it carries.
+type Message struct {
+	CallSid      string
+	RecordingSid string
+	From         string
+	To           string
+	RecordingURL string
+	// Duration of the recording in seconds
+	Duration int
+	// Status of the recording: completed, absent or failed
+	Status string
+	// Transcription is the text of the message when transcription is enabled
+	Transcription string
+	// TranscriptionStatus is completed or failed
+	TranscriptionStatus string
+}
+
+// Sink persists voicemail messages.  Messages are identified by RecordingSid.
+type Sink interface {
+	// Recorded saves a new message when the caller finishes recording
+	Recorded(ctx context.Context, m *Message) error
+	// RecordingStatus updates the URL, duration and status of the recording once it is
+	// available
+	RecordingStatus(ctx context.Context, m *Message) error
+	// Transcribed adds the transcription to a message
+	Transcribed(ctx context.Context, m *Message) error
+}
+
+// Voicemail serves the greeting and callbacks of a voicemail box under a path
+type Voicemail struct {
+	// Greeting is played before the beep and may contain Say, Play and Pause verbs
+	Greeting []twiml.Markup
+	// Goodbye is played after the message is recorded, before the call is hung up
+	Goodbye []twiml.Markup
+	// MaxLength is the longest message in seconds
+	MaxLength int
+	// FinishOnKey is the keys that end the recording
+	FinishOnKey string
+	// Transcribe asks Twilio to transcribe messages.  Twilio only transcribes recordings up
+	// to 120 seconds long and bills for each transcription.
+	Transcribe bool
+
+	path string
+	sink Sink
+}
+
+// New returns a voicemail box served under path that saves messages to sink.  Messages
+// are limited to DefaultMaxLength seconds and end on DefaultFinishOnKey.  They are not
+// transcribed unless Transcribe is set.
+func New(path string, sink Sink) *Voicemail {
+	return &Voicemail{
+		Greeting:    []twiml.Markup{&twiml.Say{Text: "Please leave a message after the beep."}},
+		MaxLength:   DefaultMaxLength,
+		FinishOnKey: DefaultFinishOnKey,
+		path:        strings.TrimSuffix(path, "/"),
+		sink:        sink,
+	}
+}
+
+// URL returns the path at which callers reach the voicemail box, for use in a Redirect
+func (v *Voicemail) URL() string {
+	return v.path
+}
+
+// Render adds the greeting and the Record verb to a response.  Flows that end in voicemail,
+// such as an unanswered Dial, may add them to their own response instead of redirecting.
+func (v *Voicemail) Render(res *twiml.Response) {
+	res.Add(v.Greeting...)
+	rec := &twiml.Record{
+		Action:                        v.path + "/recorded",
+		Method:                        http.MethodPost,
+		MaxLength:                     v.MaxLength,
+		FinishOnKey:                   v.FinishOnKey,
+		PlayBeep:                      true,
+		RecordingStatusCallback:       v.path + "/recording",
+		RecordingStatusCallbackMethod: http.MethodPost,
+		Transcribe:                    v.Transcribe,
+	}
+	if v.Transcribe {
+		rec.TranscribeCallback = v.path + "/transcription"
+	}
+	res.Add(rec)
+}
+
+// ServeHTTP serves the greeting at path and the Record callbacks at path/recorded,
+// path/recording and path/transcription
+func (v *Voicemail) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != v.path && !strings.HasPrefix(r.URL.Path, v.path+"/") {
+		http.NotFound(w, r)
+		return
+	}
+	rest := r.URL.Path[len(v.path):]
+	switch strings.Trim(rest, "/") {
+	case "":
+		res := twiml.NewResponse()
+		v.Render(res)
+		respond(w, res)
+	case "recorded":
+		v.recorded(w, r)
+	case "recording":
+		v.recording(w, r)
+	case "transcription":
+		if !v.Transcribe {
+			http.NotFound(w, r)
+			return
+		}
+		v.transcription(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// recorded handles the action of the Record verb.  Twilio requests it without a recording
+// when the caller hangs up before the beep.
+func (v *Voicemail) recorded(w http.ResponseWriter, r *http.Request) {
+	var rr twiml.RecordActionRequest
+	if err := twiml.Bind(&rr, r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if rr.RecordingURL != "" && rr.RecordingDuration > 0 {
+		m := &Message{
+			CallSid:      rr.CallSid,
+			RecordingSid: rr.RecordingSid,
+			From:         rr.From,
+			To:           rr.To,
+			RecordingURL: rr.RecordingURL,
+			Duration:     rr.RecordingDuration,
+		}
+		if err := v.sink.Recorded(r.Context(), m); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+	}
+	res := twiml.NewResponse()
+	res.Add(v.Goodbye...)
+	res.Add(&twiml.Hangup{})
+	respond(w, res)
+}
+
+func (v *Voicemail) recording(w http.ResponseWriter, r *http.Request) {
+	var rs twiml.RecordingStatusCallbackRequest
+	if err := twiml.Bind(&rs, r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	m := &Message{
+		CallSid:      rs.CallSid,
+		RecordingSid: rs.RecordingSid,
+		RecordingURL: rs.RecordingURL,
+		Duration:     rs.RecordingDuration,
+		Status:       rs.RecordingStatus,
+	}
+	saved(w, v.sink.RecordingStatus(r.Context(), m))
+}
+
+func (v *Voicemail) transcription(w http.ResponseWriter, r *http.Request) {
+	var tr twiml.TranscribeCallbackRequest
+	if err := twiml.Bind(&tr, r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	m := &Message{
+		CallSid:             tr.CallSid,
+		RecordingSid:        tr.RecordingSid,
+		From:                tr.From,
+		To:                  tr.To,
+		RecordingURL:        tr.RecordingURL,
+		Transcription:       tr.TranscriptionText,
+		TranscriptionStatus: tr.TranscriptionStatus,
+	}
+	saved(w, v.sink.Transcribed(r.Context(), m))
+}
+
+// saved answers a recording status or transcription callback, which expect no TwiML
+func saved(w http.ResponseWriter, err error) {
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func respond(w http.ResponseWriter, res *twiml.Response) {
+	b, err := res.Encode()
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.Write(b)
+}
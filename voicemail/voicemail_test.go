@@ -0,0 +1,133 @@
+package voicemail
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/BTBurke/twiml"
+	"github.com/BTBurke/twiml/twimltest"
+	"github.com/stretchr/testify/assert"
+)
+
+type sink struct {
+	recorded    []*Message
+	status      []*Message
+	transcribed []*Message
+	err         error
+}
+
+func (s *sink) Recorded(ctx context.Context, m *Message) error {
+	s.recorded = append(s.recorded, m)
+	return s.err
+}
+
+func (s *sink) RecordingStatus(ctx context.Context, m *Message) error {
+	s.status = append(s.status, m)
+	return s.err
+}
+
+func (s *sink) Transcribed(ctx context.Context, m *Message) error {
+	s.transcribed = append(s.transcribed, m)
+	return s.err
+}
+
+func TestRender(t *testing.T) {
+	vm := New("/voicemail/", &sink{})
+	res := twiml.NewResponse()
+	vm.Render(res)
+	assert.NoError(t, res.Validate())
+	assert.Equal(t, "/voicemail", vm.URL())
+
+	rec := res.Children[1].(*twiml.Record)
+	assert.Equal(t, "/voicemail/recorded", rec.Action)
+	assert.Equal(t, DefaultMaxLength, rec.MaxLength)
+	assert.Equal(t, "#", rec.FinishOnKey)
+	assert.Equal(t, "/voicemail/recording", rec.RecordingStatusCallback)
+	assert.False(t, rec.Transcribe)
+	assert.Empty(t, rec.TranscribeCallback)
+
+	vm.Transcribe = true
+	res = twiml.NewResponse()
+	vm.Render(res)
+	rec = res.Children[1].(*twiml.Record)
+	assert.True(t, rec.Transcribe)
+	assert.Equal(t, "/voicemail/transcription", rec.TranscribeCallback)
+}
+
+func TestLeaveMessage(t *testing.T) {
+	s := &sink{}
+	vm := New("/voicemail", s)
+	vm.Transcribe = true
+	vm.Goodbye = []twiml.Markup{&twiml.Say{Text: "Thanks, goodbye"}}
+
+	sim := &twimltest.Simulator{
+		Handler: vm,
+		URL:     "https://example.com/voicemail",
+		From:    "+15551112222",
+		Script:  twimltest.Script{RecordingDuration: 9},
+	}
+	res, err := sim.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"Say: Please leave a message after the beep.",
+		"Record: 9s",
+		"Say: Thanks, goodbye",
+		"Hangup",
+	}, res.Lines())
+
+	if assert.Len(t, s.recorded, 1) {
+		m := s.recorded[0]
+		assert.Equal(t, "+15551112222", m.From)
+		assert.Equal(t, twimltest.RecordingURL, m.RecordingURL)
+		assert.Equal(t, 9, m.Duration)
+		assert.NotEmpty(t, m.RecordingSid)
+		assert.Equal(t, res.Requests[0].Call().CallSid, m.CallSid)
+	}
+
+	call := res.Requests[0]
+	rec := call.RecordingComplete(twimltest.RecordingURL, 9).To("https://example.com/voicemail/recording").Record(vm)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	if assert.Len(t, s.status, 1) {
+		assert.Equal(t, "completed", s.status[0].Status)
+		assert.Equal(t, 9, s.status[0].Duration)
+	}
+
+	rec = call.Transcription("Call me back").To("https://example.com/voicemail/transcription").Record(vm)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	if assert.Len(t, s.transcribed, 1) {
+		assert.Equal(t, "Call me back", s.transcribed[0].Transcription)
+		assert.Equal(t, "completed", s.transcribed[0].TranscriptionStatus)
+		assert.Equal(t, "+15551112222", s.transcribed[0].From)
+	}
+}
+
+func TestNoRecording(t *testing.T) {
+	s := &sink{}
+	res, err := twimltest.RecordAction("", 0).To("https://example.com/voicemail/recorded").Serve(New("/voicemail", s))
+	assert.NoError(t, err)
+	assert.IsType(t, &twiml.Hangup{}, res.Children[0])
+	assert.Empty(t, s.recorded)
+}
+
+func TestSinkError(t *testing.T) {
+	vm := New("/voicemail", &sink{err: errors.New("disk full")})
+	vm.Transcribe = true
+	rec := twimltest.RecordAction(twimltest.RecordingURL, 5).To("https://example.com/voicemail/recorded").Record(vm)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	rec = twimltest.Transcription("hi").To("https://example.com/voicemail/transcription").Record(vm)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestRoutes(t *testing.T) {
+	vm := New("/voicemail", &sink{})
+	for _, path := range []string{"/other", "/voicemailrecorded", "/voicemail/other"} {
+		rec := twimltest.IncomingCall("+15551112222", "+15553334444").To("https://example.com" + path).Record(vm)
+		assert.Equal(t, http.StatusNotFound, rec.Code, path)
+	}
+
+	rec := twimltest.Transcription("hi").To("https://example.com/voicemail/transcription").Record(vm)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,116 @@
+package twiml
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SkipChildren can be returned by a WalkFunc to skip the markup nested in the current verb
+var SkipChildren = errors.New("skip children")
+
+// WalkFunc is called by Walk for each verb in a tree.  The path locates the verb relative to the
+// root, such as Gather[0]/Say[1], with verbs indexed among siblings of the same type.  The root has
+// an empty path.
+type WalkFunc func(path string, m Markup) error
+
+// Walk calls fn for the markup m and every verb nested in it, parents before their children.
+// Walk stops at the first error returned by fn other than SkipChildren.
+func Walk(m Markup, fn WalkFunc) error {
+	err := walk("", m, fn)
+	if err == SkipChildren {
+		return nil
+	}
+	return err
+}
+
+func walk(path string, m Markup, fn WalkFunc) error {
+	if err := fn(path, m); err != nil {
+		return err
+	}
+	c, ok := m.(Container)
+	if !ok {
+		return nil
+	}
+	kids := c.Kids()
+	for i, p := range kidPaths(path, kids) {
+		if err := walk(p, kids[i], fn); err != nil && err != SkipChildren {
+			return err
+		}
+	}
+	return nil
+}
+
+// Find returns every verb in the tree rooted at m for which match returns true
+func Find(m Markup, match func(path string, m Markup) bool) []Markup {
+	var found []Markup
+	Walk(m, func(path string, m Markup) error {
+		if match(path, m) {
+			found = append(found, m)
+		}
+		return nil
+	})
+	return found
+}
+
+// Replace calls fn for every verb nested in m and puts the markup it returns in place of the verb.
+// Returning the verb unchanged keeps it.  The markup nested in the replacement is visited next.
+func Replace(m Markup, fn func(path string, m Markup) Markup) {
+	replace("", m, fn)
+}
+
+func replace(path string, m Markup, fn func(path string, m Markup) Markup) {
+	c, ok := m.(Container)
+	if !ok {
+		return
+	}
+	kids := c.Kids()
+	for i, p := range kidPaths(path, kids) {
+		kids[i] = fn(p, kids[i])
+		replace(p, kids[i], fn)
+	}
+}
+
+// Filter removes every verb nested in m for which keep returns false, along with the markup
+// nested in it
+func Filter(m Markup, keep func(path string, m Markup) bool) {
+	filter("", m, keep)
+}
+
+func filter(path string, m Markup, keep func(path string, m Markup) bool) {
+	c, ok := m.(Container)
+	if !ok {
+		return
+	}
+	kids := c.Kids()
+	var kept []Markup
+	for i, p := range kidPaths(path, kids) {
+		if keep(p, kids[i]) {
+			filter(p, kids[i], keep)
+			kept = append(kept, kids[i])
+		}
+	}
+	c.SetKids(kept)
+}
+
+// kidPaths returns the path of each nested verb
+func kidPaths(path string, kids []Markup) []string {
+	names := make([]string, len(kids))
+	for i, k := range kids {
+		names[i] = elementName(k)
+	}
+	return indexedPaths(path, names)
+}
+
+// indexedPaths returns the path of each element name, indexed among siblings with the same name
+func indexedPaths(path string, names []string) []string {
+	seen := make(map[string]int)
+	paths := make([]string, len(names))
+	for i, name := range names {
+		paths[i] = fmt.Sprintf("%s[%d]", name, seen[name])
+		if path != "" {
+			paths[i] = path + "/" + paths[i]
+		}
+		seen[name]++
+	}
+	return paths
+}
@@ -0,0 +1,77 @@
+package twiml
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Walking markup trees", func() {
+	var r *Response
+
+	BeforeEach(func() {
+		r = NewResponse()
+		g := &Gather{Action: "/menu"}
+		g.Add(&Say{Text: "Press 1"}, &Pause{Length: 1}, &Say{Text: "Press 2"})
+		d := &Dial{}
+		d.Add(&Number{Number: "+15555555555"}, &Client{Name: "test"})
+		r.Add(g, d, &Hangup{})
+	})
+
+	It("visits every verb with its path", func() {
+		var paths []string
+		err := Walk(r, func(path string, m Markup) error {
+			paths = append(paths, path)
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(paths).To(Equal([]string{
+			"",
+			"Gather[0]",
+			"Gather[0]/Say[0]",
+			"Gather[0]/Pause[0]",
+			"Gather[0]/Say[1]",
+			"Dial[0]",
+			"Dial[0]/Number[0]",
+			"Dial[0]/Client[0]",
+			"Hangup[0]",
+		}))
+	})
+
+	It("can skip nested markup", func() {
+		var types []string
+		Walk(r, func(path string, m Markup) error {
+			types = append(types, m.Type())
+			if m.Type() == "Gather" {
+				return SkipChildren
+			}
+			return nil
+		})
+		Expect(types).To(Equal([]string{"Response", "Gather", "Dial", "Number", "Client", "Hangup"}))
+	})
+
+	It("can find verbs", func() {
+		says := Find(r, func(path string, m Markup) bool {
+			return m.Type() == "Say"
+		})
+		Expect(says).To(HaveLen(2))
+		Expect(says[1].(*Say).Text).To(Equal("Press 2"))
+	})
+
+	It("can replace verbs", func() {
+		Replace(r, func(path string, m Markup) Markup {
+			if s, ok := m.(*Say); ok {
+				return &Play{URL: "https://example.com/" + s.Text + ".mp3"}
+			}
+			return m
+		})
+		Expect(r.Children[0].(*Gather).Children[0]).To(Equal(&Play{URL: "https://example.com/Press 1.mp3"}))
+	})
+
+	It("can filter verbs", func() {
+		Filter(r, func(path string, m Markup) bool {
+			return m.Type() != "Pause" && path != "Dial[0]/Client[0]"
+		})
+		Expect(r.Children[0].(*Gather).Children).To(HaveLen(2))
+		Expect(r.Children[1].(*Dial).Children).To(Equal([]Markup{&Number{Number: "+15555555555"}}))
+	})
+})